@@ -1,16 +1,26 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 
 	"hackflow-api/internal/config"
 	"hackflow-api/internal/database"
 	"hackflow-api/internal/handlers"
+	"hackflow-api/internal/httpclient"
+	"hackflow-api/internal/llmusage"
 	"hackflow-api/internal/logger"
+	"hackflow-api/internal/middleware"
+	"hackflow-api/internal/pagefetch"
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/taxonomy"
+	"hackflow-api/internal/websearch"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
 )
 
 func main() {
@@ -29,8 +39,62 @@ func main() {
 	}
 
 	// 4. Initialize HTTP Handlers with DB Dependency
-	h := handlers.New(db)
-	aiHandler := handlers.NewSearchAIHandler(cfg)
+	httpClient, err := httpclient.NewFromConfig(cfg)
+	if err != nil {
+		slog.Error("Critical error: invalid outbound HTTP configuration", "error", err)
+		return
+	}
+
+	searcher, err := websearch.New(cfg, httpClient)
+	if err != nil {
+		slog.Error("Critical error: unable to initialize web search provider", "error", err)
+		return
+	}
+	slog.Info("Web search provider selected", "provider", searcher.Name())
+
+	// One Gemini client for the whole process, so connections are reused across requests
+	var gemini *genai.Client
+	if cfg.GeminiAPIKey != "" {
+		gemini, err = genai.NewClient(context.Background(), option.WithAPIKey(cfg.GeminiAPIKey))
+		if err != nil {
+			slog.Error("Critical error: unable to initialize Gemini client", "error", err)
+			return
+		}
+		defer gemini.Close()
+	} else {
+		slog.Warn("GEMINI_API_KEY is not set, AI search is disabled")
+	}
+
+	regions, err := region.Load(cfg.RegionsConfig, cfg.Region, cfg.Language)
+	if err != nil {
+		slog.Error("Critical error: unable to load regions config", "error", err)
+		return
+	}
+
+	if err := database.SyncCities(db, regions); err != nil {
+		slog.Error("Critical error: unable to sync cities", "error", err)
+		return
+	}
+
+	tags, err := taxonomy.Load(cfg.TagsConfig)
+	if err != nil {
+		slog.Error("Critical error: unable to load tags config", "error", err)
+		return
+	}
+
+	if err := database.SyncTags(db, tags); err != nil {
+		slog.Error("Critical error: unable to sync tags", "error", err)
+		return
+	}
+
+	h := handlers.New(db, regions, tags)
+	pages := pagefetch.New(httpClient, cfg.PageFetchTopN, cfg.PageFetchTokenBudget, cfg.PageFetchCacheTTL)
+	searchCache := handlers.NewSearchCache(db, cfg.SearchCacheSize, cfg.SearchCacheTTL, cfg.SearchCachePersist)
+	usage := llmusage.NewFromConfig(db, cfg)
+	aiHandler := handlers.NewSearchAIHandler(cfg, db, gemini, searcher, pages, searchCache, regions, usage, tags)
+	hybridHandler := handlers.NewHybridSearchHandler(db, aiHandler, cfg.HybridMinResults)
+	adminHandler := handlers.NewAdminHandler(db, regions, tags)
+	usageHandler := handlers.NewUsageHandler(usage)
 
 	// 5. Initialize Gin Router
 	if cfg.Env == "production" || cfg.Env == "prod" {
@@ -41,15 +105,36 @@ func main() {
 	// Configure CORS for Next.js frontend
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowOrigins = []string{"http://localhost:3000"}
-	corsConfig.AllowMethods = []string{"GET", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept"}
+	corsConfig.AllowMethods = []string{"GET", "POST", "PATCH", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	corsConfig.ExposeHeaders = []string{"X-Web-Search", "X-Cache"}
 	r.Use(cors.New(corsConfig))
 
+	// Posters downloaded by the scraper (shared volume)
+	r.Static("/media", cfg.MediaDir)
+
+	// LLM usage counters for Prometheus
+	r.GET("/metrics", middleware.AdminAuth(cfg.AdminToken), usageHandler.Metrics)
+
 	// Defines API Routes
 	api := r.Group("/api")
 	{
 		api.GET("/hackathons", h.GetHackathons)
-		api.GET("/search", aiHandler.SearchAI)
+		api.GET("/cities", h.GetCities)
+		api.GET("/tags", h.GetTags)
+		api.GET("/search", middleware.Timeout(cfg.SearchTimeout), aiHandler.SearchAI)
+		api.GET("/search/hybrid", middleware.Timeout(cfg.SearchTimeout), hybridHandler.HybridSearch)
+		api.GET("/search/stream", middleware.Timeout(cfg.StreamSearchTimeout), aiHandler.SearchAIStream)
+
+		// Moderation queue for AI search discoveries
+		admin := api.Group("/admin", middleware.AdminAuth(cfg.AdminToken))
+		{
+			admin.GET("/hackathons", adminHandler.ListHackathons)
+			admin.PATCH("/hackathons/:id", adminHandler.UpdateHackathon)
+			admin.POST("/hackathons/:id/approve", adminHandler.ApproveHackathon)
+			admin.POST("/hackathons/:id/reject", adminHandler.RejectHackathon)
+			admin.GET("/llm-usage", usageHandler.GetUsage)
+		}
 	}
 
 	// 6. Start the Server
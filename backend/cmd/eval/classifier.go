@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"hackflow-api/internal/classifier"
+)
+
+func runClassifierEval(args []string) error {
+	fs := flag.NewFlagSet("classifier", flag.ExitOnError)
+	corpusPath := fs.String("corpus", "internal/classifier/testdata/corpus.jsonl", "labeled corpus (JSON Lines)")
+	configPath := fs.String("config", "", "classifier config (default: built-in)")
+	minPrecision := fs.Float64("min-precision", 0, "fail if precision is below this value")
+	minRecall := fs.Float64("min-recall", 0, "fail if recall is below this value")
+	fs.Parse(args)
+
+	cfg, err := classifier.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	// The LLM stage needs network access and a key; evaluate keywords only.
+	c, err := classifier.New(cfg, nil)
+	if err != nil {
+		return err
+	}
+
+	samples, err := classifier.LoadCorpus(*corpusPath)
+	if err != nil {
+		return err
+	}
+
+	m, err := classifier.Evaluate(context.Background(), c, samples)
+	if err != nil {
+		return err
+	}
+
+	if len(m.Misses) > 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tLABEL\tSCORE\tMATCHES")
+		for _, miss := range m.Misses {
+			fmt.Fprintf(w, "%s\t%v\t%.2f\t%v\n", miss.Sample.ID, miss.Sample.Label, miss.Result.Score, miss.Result.Matches)
+		}
+		w.Flush()
+		fmt.Println()
+	}
+
+	fmt.Printf("samples=%d tp=%d fp=%d tn=%d fn=%d\n", len(samples), m.TruePositives, m.FalsePositives, m.TrueNegatives, m.FalseNegatives)
+	fmt.Printf("precision=%.3f recall=%.3f f1=%.3f\n", m.Precision(), m.Recall(), m.F1())
+
+	if m.Precision() < *minPrecision || m.Recall() < *minRecall {
+		return fmt.Errorf("quality below thresholds (precision >= %.2f, recall >= %.2f)", *minPrecision, *minRecall)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"hackflow-api/internal/config"
+	"hackflow-api/internal/extract"
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/taxonomy"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+)
+
+func runExtractionEval(args []string) error {
+	fs := flag.NewFlagSet("extraction", flag.ExitOnError)
+	corpusPath := fs.String("corpus", "internal/extract/testdata/corpus.jsonl", "labeled corpus (JSON Lines)")
+	baselinePath := fs.String("baseline", "internal/extract/testdata/baseline.json", "stored baseline to compare with")
+	updateBaseline := fs.Bool("update-baseline", false, "overwrite the baseline with this run")
+	predictionsPath := fs.String("predictions", "internal/extract/testdata/predictions.jsonl", "stored predictions replayed instead of calling Gemini")
+	live := fs.Bool("live", false, "call Gemini instead of replaying -predictions")
+	savePath := fs.String("save-predictions", "", "store this run's predictions for offline replay")
+	model := fs.String("model", extract.DefaultModel, "Gemini model")
+	batch := fs.Int("batch", 1, "with -live, posts per model call (the scraper's batch prompt when > 1)")
+	showDiffs := fs.Bool("diffs", true, "print every mismatching field")
+	fs.Parse(args)
+
+	cfg := config.Load()
+	regions, err := region.Load(cfg.RegionsConfig, cfg.Region, cfg.Language)
+	if err != nil {
+		return err
+	}
+	loc, err := regions.Resolve("", "")
+	if err != nil {
+		return err
+	}
+
+	samples, err := extract.LoadCorpus(*corpusPath)
+	if err != nil {
+		return err
+	}
+
+	var (
+		ex extract.Extractor
+		bx extract.BatchExtractor
+	)
+	if !*live {
+		if ex, err = extract.NewReplay(*predictionsPath, samples); err != nil {
+			return err
+		}
+	} else {
+		if cfg.GeminiAPIKey == "" {
+			return errors.New("GEMINI_API_KEY is required with -live")
+		}
+		client, err := genai.NewClient(context.Background(), option.WithAPIKey(cfg.GeminiAPIKey))
+		if err != nil {
+			return fmt.Errorf("failed to initialize Gemini: %w", err)
+		}
+		defer client.Close()
+
+		tags, err := taxonomy.Load(cfg.TagsConfig)
+		if err != nil {
+			return err
+		}
+
+		g := extract.NewGemini(client, loc)
+		g.Model = *model
+		g.Tags = tags
+		ex = g
+		if *batch > 1 {
+			bx = g
+		}
+	}
+
+	var report extract.Report
+	if bx != nil {
+		report, err = extract.EvaluateBatch(context.Background(), bx, samples, loc, *batch)
+	} else {
+		report, err = extract.Evaluate(context.Background(), ex, samples, loc)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *savePath != "" {
+		if err := extract.SavePredictions(*savePath, report); err != nil {
+			return err
+		}
+	}
+
+	if *showDiffs && len(report.Diffs) > 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tFIELD\tEXPECTED\tGOT")
+		for _, d := range report.Diffs {
+			fmt.Fprintf(w, "%s\t%s\t%q\t%q\n", d.SampleID, d.Field, d.Expected, d.Got)
+		}
+		w.Flush()
+		fmt.Println()
+	}
+
+	baseline, err := extract.LoadBaseline(*baselinePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tACCURACY\tBASELINE\tDELTA")
+	for _, field := range extract.Fields {
+		acc := report.Accuracy(field)
+		if baseline == nil {
+			fmt.Fprintf(w, "%s\t%.3f\t-\t-\n", field, acc)
+			continue
+		}
+		base := baseline.Accuracy[field]
+		fmt.Fprintf(w, "%s\t%.3f\t%.3f\t%+.3f\n", field, acc, base, acc-base)
+	}
+	w.Flush()
+	fmt.Printf("\nsamples=%d errors=%d prompt=%s\n", len(samples), report.Errors(), report.PromptVersion)
+
+	if *updateBaseline {
+		if err := extract.SaveBaseline(*baselinePath, report.Baseline(*model)); err != nil {
+			return err
+		}
+		fmt.Println("baseline updated:", *baselinePath)
+		return nil
+	}
+	if baseline == nil {
+		fmt.Println("no baseline at", *baselinePath, "- run with -update-baseline to create one")
+		return nil
+	}
+
+	fmt.Printf("baseline: prompt=%s model=%s\n", baseline.PromptVersion, baseline.Model)
+	regressions := report.Regressions(baseline)
+	if len(regressions) == 0 {
+		return nil
+	}
+
+	fmt.Println("\nregressions:")
+	w = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, d := range regressions {
+		fmt.Fprintf(w, "%s\t%s\t%q\t%q\n", d.SampleID, d.Field, d.Expected, d.Got)
+	}
+	w.Flush()
+	return fmt.Errorf("%d field(s) regressed against the baseline", len(regressions))
+}
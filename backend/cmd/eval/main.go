@@ -0,0 +1,44 @@
+// Command eval measures the quality of HackFlow's offline pipeline stages
+// against labeled corpora.
+//
+// Usage:
+//
+//	go run ./cmd/eval classifier [-corpus path] [-config path] [-min-precision 0.9] [-min-recall 0.9]
+//	go run ./cmd/eval extraction [-corpus path] [-baseline path] [-update-baseline] [-predictions path] [-live [-batch 5]] [-save-predictions path]
+//
+// The extraction eval replays the predictions stored next to the corpus by
+// default, so it runs offline; -live calls Gemini instead, and -batch sends
+// several posts per call with the scraper's batch prompt.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "classifier":
+		err = runClassifierEval(os.Args[2:])
+	case "extraction":
+		err = runExtractionEval(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "eval:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: eval <classifier|extraction> [flags]")
+}
@@ -0,0 +1,100 @@
+// Command prompt renders the LLM prompts exactly as the scraper and the web
+// agent would send them, so prompt changes can be reviewed as plain text
+// diffs before they ship.
+//
+// Usage:
+//
+//	go run ./cmd/prompt versions
+//	go run ./cmd/prompt post [-file post.txt | -link https://t.me/<channel>/<id>] [-published 2026-03-01] [-date 2026-03-10] [-region kz] [-lang ru]
+//	go run ./cmd/prompt posts [-published 2026-03-01] post1.txt post2.txt ... | -corpus internal/extract/testdata/corpus.jsonl [-n 5]
+//	go run ./cmd/prompt verify [-file post.txt]
+//	go run ./cmd/prompt search -query "ai хакатон" [-fixture path] [-date 2026-03-10] [-region kz] [-lang ru]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"hackflow-api/internal/config"
+	"hackflow-api/internal/prompts"
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/taxonomy"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "versions":
+		for _, name := range prompts.Names() {
+			fmt.Printf("%s\t%s\n", name, prompts.Versions()[name])
+		}
+	case "post":
+		err = renderPost(os.Args[2:])
+	case "posts":
+		err = renderPosts(os.Args[2:])
+	case "search":
+		err = renderSearch(os.Args[2:])
+	case "verify":
+		err = renderVerify(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "prompt:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: prompt <versions|post|posts|search|verify> [flags]")
+}
+
+// localeFlags are shared by every render subcommand.
+type localeFlags struct {
+	date   *string
+	region *string
+	lang   *string
+}
+
+func addLocaleFlags(fs *flag.FlagSet) localeFlags {
+	return localeFlags{
+		date:   fs.String("date", time.Now().Format("2006-01-02"), "\"today\" as seen by the prompt (YYYY-MM-DD)"),
+		region: fs.String("region", "", "region code (default: REGION)"),
+		lang:   fs.String("lang", "", "output language (default: OUTPUT_LANGUAGE or the region's)"),
+	}
+}
+
+func (f localeFlags) resolve(cfg *config.Config) (region.Locale, error) {
+	if _, err := time.Parse("2006-01-02", *f.date); err != nil {
+		return region.Locale{}, fmt.Errorf("invalid -date: %w", err)
+	}
+
+	regions, err := region.Load(cfg.RegionsConfig, cfg.Region, cfg.Language)
+	if err != nil {
+		return region.Locale{}, err
+	}
+	return regions.Resolve(*f.region, *f.lang)
+}
+
+// tagSlugs returns the taxonomy slugs offered to the model.
+func tagSlugs(cfg *config.Config) ([]string, error) {
+	tags, err := taxonomy.Load(cfg.TagsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return tags.Slugs(), nil
+}
+
+// printPrompt writes the version header and the prompt text to stdout.
+func printPrompt(p prompts.Prompt) {
+	fmt.Printf("# %s (%s)\n\n%s\n", p.Name, p.Version, p.Text)
+}
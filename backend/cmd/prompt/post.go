@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"hackflow-api/internal/config"
+	"hackflow-api/internal/httpclient"
+	"hackflow-api/internal/prompts"
+	"hackflow-api/internal/telegram"
+)
+
+func renderPost(args []string) error {
+	fs := flag.NewFlagSet("post", flag.ExitOnError)
+	file := fs.String("file", "-", "post text file (- for stdin)")
+	link := fs.String("link", "", "fetch the post from Telegram by permalink instead of -file")
+	published := fs.String("published", "", "publication date of a -file post (default: -date)")
+	lf := addLocaleFlags(fs)
+	fs.Parse(args)
+
+	cfg := config.Load()
+	loc, err := lf.resolve(cfg)
+	if err != nil {
+		return err
+	}
+
+	var post telegram.Post
+	if *link != "" {
+		post, err = fetchPost(cfg, *link)
+	} else {
+		post, err = readPost(*file, *published, *lf.date)
+	}
+	if err != nil {
+		return err
+	}
+
+	tags, err := tagSlugs(cfg)
+	if err != nil {
+		return err
+	}
+
+	p, err := prompts.Post(prompts.PostData{
+		Date:        *lf.date,
+		PublishedAt: post.PublishedAt.Format("2006-01-02"),
+		Text:        post.Text,
+		Links:       post.Links,
+		Locale:      loc,
+		Tags:        tags,
+	})
+	if err != nil {
+		return err
+	}
+	printPrompt(p)
+	return nil
+}
+
+func readPost(path, published, date string) (telegram.Post, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return telegram.Post{}, fmt.Errorf("failed to read post: %w", err)
+	}
+
+	if published == "" {
+		published = date
+	}
+	publishedAt, err := time.Parse("2006-01-02", published)
+	if err != nil {
+		return telegram.Post{}, fmt.Errorf("invalid -published: %w", err)
+	}
+
+	return telegram.Post{Text: strings.TrimSpace(string(data)), PublishedAt: publishedAt}, nil
+}
+
+// fetchPost finds a post by permalink on its channel's web preview. Only the
+// posts shown on the first page (the most recent ones) can be found.
+func fetchPost(cfg *config.Config, link string) (telegram.Post, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return telegram.Post{}, fmt.Errorf("invalid -link: %w", err)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 2 {
+		return telegram.Post{}, errors.New("invalid -link: expected https://t.me/<channel>/<id>")
+	}
+	channel, id := parts[0], parts[1]
+
+	httpClient, err := httpclient.NewFromConfig(cfg)
+	if err != nil {
+		return telegram.Post{}, err
+	}
+	posts, err := telegram.NewClient(httpClient, cfg.TelegramBaseURL).FetchChannel(context.Background(), channel)
+	if err != nil {
+		return telegram.Post{}, err
+	}
+
+	for _, post := range posts {
+		if strings.HasSuffix(post.Permalink, "/"+channel+"/"+id) {
+			return post, nil
+		}
+	}
+	return telegram.Post{}, fmt.Errorf("post %s/%s not found among the %d latest posts of the channel", channel, id, len(posts))
+}
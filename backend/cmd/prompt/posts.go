@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"flag"
+
+	"hackflow-api/internal/config"
+	"hackflow-api/internal/extract"
+	"hackflow-api/internal/prompts"
+	"hackflow-api/internal/telegram"
+)
+
+func renderPosts(args []string) error {
+	fs := flag.NewFlagSet("posts", flag.ExitOnError)
+	published := fs.String("published", "", "publication date of the file posts (default: -date)")
+	corpus := fs.String("corpus", "", "take the posts from a labeled corpus (JSON Lines) instead of files")
+	n := fs.Int("n", 5, "number of corpus posts in the batch (as EXTRACT_BATCH_SIZE)")
+	lf := addLocaleFlags(fs)
+	fs.Parse(args)
+
+	cfg := config.Load()
+	loc, err := lf.resolve(cfg)
+	if err != nil {
+		return err
+	}
+
+	var posts []telegram.Post
+	if *corpus != "" {
+		posts, err = corpusPosts(*corpus, *n)
+	} else {
+		posts, err = filePosts(fs.Args(), *published, *lf.date)
+	}
+	if err != nil {
+		return err
+	}
+
+	tags, err := tagSlugs(cfg)
+	if err != nil {
+		return err
+	}
+
+	batch := make([]prompts.BatchPost, len(posts))
+	for i, post := range posts {
+		batch[i] = prompts.BatchPost{
+			PublishedAt: post.PublishedAt.Format("2006-01-02"),
+			Text:        post.Text,
+			Links:       post.Links,
+		}
+	}
+	p, err := prompts.Posts(prompts.PostsData{
+		Date:   *lf.date,
+		Posts:  batch,
+		Locale: loc,
+		Tags:   tags,
+	})
+	if err != nil {
+		return err
+	}
+	printPrompt(p)
+	return nil
+}
+
+func filePosts(paths []string, published, date string) ([]telegram.Post, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("pass post text files as arguments or use -corpus")
+	}
+	posts := make([]telegram.Post, len(paths))
+	for i, path := range paths {
+		post, err := readPost(path, published, date)
+		if err != nil {
+			return nil, err
+		}
+		posts[i] = post
+	}
+	return posts, nil
+}
+
+func corpusPosts(path string, n int) ([]telegram.Post, error) {
+	samples, err := extract.LoadCorpus(path)
+	if err != nil {
+		return nil, err
+	}
+	samples = samples[:min(max(n, 1), len(samples))]
+
+	posts := make([]telegram.Post, len(samples))
+	for i, s := range samples {
+		post, _, err := s.Post()
+		if err != nil {
+			return nil, err
+		}
+		posts[i] = post
+	}
+	return posts, nil
+}
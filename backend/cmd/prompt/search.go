@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"hackflow-api/internal/config"
+	"hackflow-api/internal/prompts"
+	"hackflow-api/internal/websearch"
+)
+
+func renderSearch(args []string) error {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	query := fs.String("query", "", "user query")
+	fixture := fs.String("fixture", "testdata/websearch/hackathons.json", "web search results (JSON array, as for WEB_SEARCH_PROVIDER=static)")
+	lf := addLocaleFlags(fs)
+	fs.Parse(args)
+
+	if *query == "" {
+		return errors.New("-query is required")
+	}
+
+	cfg := config.Load()
+	loc, err := lf.resolve(cfg)
+	if err != nil {
+		return err
+	}
+
+	searcher, err := websearch.NewStatic(*fixture)
+	if err != nil {
+		return err
+	}
+	results, err := searcher.Search(context.Background(), *query, 5)
+	if err != nil {
+		return err
+	}
+
+	tags, err := tagSlugs(cfg)
+	if err != nil {
+		return err
+	}
+
+	p, err := prompts.Search(prompts.SearchData{
+		Date:    *lf.date,
+		Query:   *query,
+		Results: results,
+		Locale:  loc,
+		Tags:    tags,
+	})
+	if err != nil {
+		return err
+	}
+	printPrompt(p)
+	return nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"hackflow-api/internal/guard"
+	"hackflow-api/internal/prompts"
+)
+
+func renderVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	file := fs.String("file", "-", "post text file (- for stdin)")
+	fs.Parse(args)
+
+	post, err := readPost(*file, "", time.Now().Format("2006-01-02"))
+	if err != nil {
+		return err
+	}
+
+	// The scraper sanitizes the post before the classifier's check
+	text, _ := guard.Sanitize(post.Text)
+	p, err := prompts.Verify(prompts.VerifyData{Text: text})
+	if err != nil {
+		return err
+	}
+	printPrompt(p)
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"hackflow-api/internal/config"
+	"hackflow-api/internal/handlers"
+	"hackflow-api/internal/middleware"
+	"hackflow-api/internal/scheduler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serveAdmin поднимает служебный HTTP-сервер для ручного запуска парсинга
+// источников и просмотра расхода токенов Gemini
+func serveAdmin(ctx context.Context, cfg *config.Config, sched *scheduler.Scheduler) {
+	if cfg.Env == "production" || cfg.Env == "prod" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	usageHandler := handlers.NewUsageHandler(usage)
+
+	admin := r.Group("/admin", middleware.AdminAuth(cfg.AdminToken))
+	{
+		admin.GET("/llm-usage", usageHandler.GetUsage)
+		admin.GET("/metrics", usageHandler.Metrics)
+
+		admin.GET("/sources", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"sources": sched.Jobs()})
+		})
+
+		admin.POST("/scrape/:source", func(c *gin.Context) {
+			source := c.Param("source")
+
+			// Задача живет дольше HTTP-запроса, поэтому используем корневой контекст
+			err := sched.Trigger(ctx, source)
+			switch {
+			case errors.Is(err, scheduler.ErrUnknownJob):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Unknown source"})
+			case errors.Is(err, scheduler.ErrAlreadyRunning):
+				c.JSON(http.StatusConflict, gin.H{"error": "Source is already being scraped"})
+			case err != nil:
+				slog.Error("Ошибка ручного запуска", "channel", source, "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to trigger scrape"})
+			default:
+				slog.Info("Ручной запуск парсинга", "channel", source)
+				c.JSON(http.StatusAccepted, gin.H{"status": "started", "source": source})
+			}
+		})
+	}
+
+	srv := &http.Server{Addr: ":" + cfg.ScraperAdminPort, Handler: r}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("Админ-сервер парсера запущен", "address", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		slog.Error("Ошибка админ-сервера парсера", "error", err)
+	}
+}
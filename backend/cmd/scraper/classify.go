@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"hackflow-api/internal/guard"
+	"hackflow-api/internal/llmusage"
+	"hackflow-api/internal/prompts"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// geminiVerifier — дешевая yes/no проверка для постов с неоднозначным скором
+// (клиент общий с извлечением полей)
+type geminiVerifier struct {
+	client *genai.Client
+	usage  *llmusage.Tracker
+}
+
+// verifierModel — модель для проверки постов классификатором
+const verifierModel = "gemini-2.5-flash-lite"
+
+func (v *geminiVerifier) Verify(ctx context.Context, text string) (bool, error) {
+	// При исчерпанном бюджете классификатор оставит решение по ключевым словам
+	if err := v.usage.Allow(ctx, llmusage.CallerClassifier); err != nil {
+		return false, err
+	}
+
+	// Пост — недоверенный текст: строки, похожие на команды модели, убираем,
+	// а теги-разделители экранирует шаблон, чтобы пост не мог «ответить» за модель
+	text, found := guard.Sanitize(text)
+	if len(found) > 0 {
+		slog.Warn("Попытка prompt-инъекции в посте на проверке классификатором", "patterns", found)
+	}
+
+	prompt, err := prompts.Verify(prompts.VerifyData{Text: text})
+	if err != nil {
+		return false, err
+	}
+
+	model := v.client.GenerativeModel(verifierModel)
+	model.SetTemperature(0)
+	model.SetMaxOutputTokens(5)
+
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt.Text))
+	v.usage.Record(ctx, llmusage.CallerClassifier, verifierModel, llmusage.FromResponse(resp))
+	if err != nil {
+		return false, err
+	}
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return false, fmt.Errorf("пустой ответ от Gemini")
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])))
+	slog.Debug("Ответ ИИ-проверки классификатора", "answer", answer, "prompt_version", prompt.Version)
+	return strings.HasPrefix(answer, "yes"), nil
+}
@@ -2,46 +2,57 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
-	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
+	"hackflow-api/internal/classifier"
 	"hackflow-api/internal/config"
 	"hackflow-api/internal/database"
+	"hackflow-api/internal/extract"
+	"hackflow-api/internal/httpclient"
+	"hackflow-api/internal/llmusage"
 	"hackflow-api/internal/logger"
+	"hackflow-api/internal/media"
 	"hackflow-api/internal/models"
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/scheduler"
+	"hackflow-api/internal/storage"
+	"hackflow-api/internal/taxonomy"
+	"hackflow-api/internal/telegram"
 
-	"github.com/PuerkitoBio/goquery"
 	"github.com/google/generative-ai-go/genai"
 	"google.golang.org/api/option"
 	"gorm.io/gorm"
 )
 
-var db *gorm.DB
-
-// AIResponse - промежуточная структура для строгого JSON от ИИ
-type AIResponse struct {
-	Title    string  `json:"title"`
-	DateStr  string  `json:"date_str"`
-	Deadline string  `json:"deadline"`
-	Format   string  `json:"format"`
-	City     *string `json:"city"`
-	AgeLimit string  `json:"ageLimit"`
-	Link     *string `json:"link"`
-	Status   string  `json:"status"`
-}
-
-// ScrapedPost структура для передачи текста и даты публикации
-type ScrapedPost struct {
-	Text        string
-	PublishedAt time.Time
-}
+var (
+	db        *gorm.DB
+	clsf      *classifier.Classifier
+	mediaProc *media.Processor
+	tg        *telegram.Client
+	// locale — регион и язык, в которых сохраняются извлеченные поля
+	locale    region.Locale
+	extractor extract.Extractor
+	// usage — учет токенов Gemini и дневные бюджеты парсера и классификатора
+	usage *llmusage.Tracker
+	// batchSize — сколько постов извлекается одним вызовом модели
+	batchSize int
+	// tags — справочник тематик, из которого ИИ и ключевые слова выбирают теги
+	tags *taxonomy.Taxonomy
+)
 
 func main() {
+	once := flag.Bool("once", false, "выполнить один проход по источникам и завершиться (для batch-джобов)")
+	sources := flag.String("source", "", "список каналов через запятую для режима --once (по умолчанию все)")
+	flag.Parse()
+
 	cfg := config.Load()
 	logger.Setup(cfg.Env)
 
@@ -54,209 +65,365 @@ func main() {
 		os.Exit(1)
 	}
 
-	apiKey := os.Getenv("GEMINI_API_KEY")
+	apiKey := cfg.GeminiAPIKey
 	if apiKey == "" {
 		slog.Error("Не найден GEMINI_API_KEY в переменных окружения")
 		os.Exit(1)
 	}
 
-	// Первый запуск сразу после старта контейнера
-	runScraper(apiKey)
-
-	// Запускаем парсер каждые 6 часов
-	ticker := time.NewTicker(6 * time.Hour)
-	defer ticker.Stop()
-
-	slog.Info("Парсер переведен в фоновый режим. Следующий запуск через 6 часов.")
-	for range ticker.C {
-		runScraper(apiKey)
+	clsfCfg, err := classifier.LoadConfig(cfg.ClassifierConfig)
+	if err != nil {
+		slog.Error("Ошибка загрузки конфигурации классификатора", "error", err)
+		os.Exit(1)
 	}
-}
-
-func runScraper(apiKey string) {
-	channels := []string{"astanahub", "uppertunity", "nuris_nu", "terriconvalley", "bluescreenkz", "kolesa_team", "tce_kz", "hackathons_ru"}
-
-	twoMonthsAgo := time.Now().AddDate(0, -2, 0)
-
-	for _, channel := range channels {
-		slog.Info("Парсинг канала", "channel", channel)
-		posts, err := scrapeChannel(channel)
-		if err != nil {
-			slog.Error("Ошибка парсинга", "channel", channel, "error", err)
-			continue
-		}
+	usage = llmusage.NewFromConfig(db, cfg)
 
-		slog.Info("Найдено потенциальных хакатонов", "count", len(posts), "channel", channel)
+	// Один клиент Gemini на весь процесс: соединения переиспользуются между постами
+	gemini, err := genai.NewClient(context.Background(), option.WithAPIKey(apiKey))
+	if err != nil {
+		slog.Error("Ошибка инициализации клиента Gemini", "error", err)
+		os.Exit(1)
+	}
+	defer gemini.Close()
 
-		for _, post := range posts {
-			// Игнор старья: пропускаем посты старше 2 месяцев
-			if post.PublishedAt.Before(twoMonthsAgo) {
-				slog.Debug("Пропуск слишком старого поста", "published_at", post.PublishedAt)
-				continue
-			}
+	clsf, err = classifier.New(clsfCfg, &geminiVerifier{client: gemini, usage: usage})
+	if err != nil {
+		slog.Error("Ошибка инициализации классификатора", "error", err)
+		os.Exit(1)
+	}
 
-			hackathon := parseWithAI(post, apiKey)
-			if hackathon == nil {
-				continue
-			}
+	regions, err := region.Load(cfg.RegionsConfig, cfg.Region, cfg.Language)
+	if err != nil {
+		slog.Error("Ошибка загрузки конфигурации регионов", "error", err)
+		os.Exit(1)
+	}
+	locale, err = regions.Resolve("", "")
+	if err != nil {
+		slog.Error("Ошибка выбора региона", "error", err)
+		os.Exit(1)
+	}
+	if err := database.SyncCities(db, regions); err != nil {
+		slog.Error("Ошибка заполнения справочника городов", "error", err)
+		os.Exit(1)
+	}
 
-			var existing models.Hackathon
-			res := db.Where("title = ?", hackathon.Title).First(&existing)
-			if res.Error == nil {
-				slog.Info("Хакатон уже существует, пропускаем", "title", hackathon.Title)
-				continue
-			} else if res.Error != gorm.ErrRecordNotFound {
-				slog.Error("Ошибка проверки дубликата", "error", res.Error)
-				continue
-			}
+	tags, err = taxonomy.Load(cfg.TagsConfig)
+	if err != nil {
+		slog.Error("Ошибка загрузки справочника тематик", "error", err)
+		os.Exit(1)
+	}
+	if err := database.SyncTags(db, tags); err != nil {
+		slog.Error("Ошибка заполнения справочника тематик", "error", err)
+		os.Exit(1)
+	}
 
-			if err := db.Create(hackathon).Error; err != nil {
-				slog.Error("Ошибка сохранения хакатона", "title", hackathon.Title, "error", err)
-			} else {
-				slog.Info("✅ Успешно добавлен новый хакатон!", "title", hackathon.Title)
-			}
+	ex := extract.NewGemini(gemini, locale)
+	ex.Usage = usage
+	ex.Tags = tags
+	extractor = ex
+	batchSize = max(cfg.ExtractBatchSize, 1)
 
-			time.Sleep(3 * time.Second)
-		}
+	store, err := storage.NewLocalStore(cfg.MediaDir, cfg.MediaBaseURL)
+	if err != nil {
+		slog.Error("Ошибка инициализации хранилища медиа", "error", err)
+		os.Exit(1)
 	}
-	slog.Info("Текущий цикл парсинга завершен!")
-}
+	httpClient, err := httpclient.NewFromConfig(cfg)
+	if err != nil {
+		slog.Error("Ошибка настройки HTTP-клиента", "error", err)
+		os.Exit(1)
+	}
+	tg = telegram.NewClient(httpClient, cfg.TelegramBaseURL)
+	mediaProc = media.NewProcessor(store, httpClient)
 
-// scrapeChannel парсит Telegram Web Preview и извлекает тексты постов с датами
-func scrapeChannel(channelName string) ([]ScrapedPost, error) {
-	url := fmt.Sprintf("https://t.me/s/%s", channelName)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	res, err := http.Get(url)
+	sched, err := newScheduler(cfg)
 	if err != nil {
-		return nil, err
+		slog.Error("Ошибка настройки расписания", "error", err)
+		os.Exit(1)
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("статус код ошибки: %d", res.StatusCode)
+	if *once {
+		runOnce(ctx, sched, *sources)
+		return
 	}
 
-	doc, err := goquery.NewDocumentFromReader(res.Body)
-	if err != nil {
-		return nil, err
+	if cfg.ScraperAdminPort != "" {
+		go serveAdmin(ctx, cfg, sched)
 	}
 
-	var posts []ScrapedPost
+	// Первый запуск сразу после старта контейнера
+	sched.RunAllBackground(ctx)
 
-	doc.Find(".tgme_widget_message").Each(func(i int, s *goquery.Selection) {
-		textSelection := s.Find(".tgme_widget_message_text")
-		if textSelection.Length() == 0 {
-			return
-		}
+	sched.Start(ctx)
+	slog.Info("Парсер переведен в фоновый режим", "schedule", cfg.ScraperSchedule, "jitter", cfg.ScraperJitter)
 
-		text := strings.TrimSpace(textSelection.Text())
-		lowerText := strings.ToLower(text)
+	<-ctx.Done()
+	slog.Info("Получен сигнал остановки, ждем завершения текущих задач")
+	sched.Wait()
+}
 
-		// Находим тег <time> с датой
-		timeAttr, exists := s.Find("time").Attr("datetime")
-		if !exists {
-			return
-		}
+// newScheduler регистрирует по одной задаче на каждый канал с его собственным расписанием
+func newScheduler(cfg *config.Config) (*scheduler.Scheduler, error) {
+	sched := scheduler.New(cfg.ScraperJitter)
 
-		// Парсим дату стандарта ISO (напр. 2024-02-21T15:04:05+00:00)
-		publishedAt, err := time.Parse(time.RFC3339, timeAttr)
+	for _, channel := range cfg.ScraperChannels {
+		expr := cfg.SourceSchedule(channel)
+		schedule, err := scheduler.Parse(expr)
 		if err != nil {
-			return
+			return nil, fmt.Errorf("канал %s: %w", channel, err)
 		}
 
-		// Оставляем только посты с 언급анием хакатонов
-		if strings.Contains(lowerText, "хакатон") || strings.Contains(lowerText, "hackathon") {
-			posts = append(posts, ScrapedPost{
-				Text:        text,
-				PublishedAt: publishedAt,
-			})
+		if err := sched.Add(channel, schedule, func(ctx context.Context) error {
+			return runChannelExclusive(ctx, channel)
+		}); err != nil {
+			return nil, err
 		}
-	})
+		slog.Debug("Канал добавлен в расписание", "channel", channel, "schedule", expr)
+	}
 
-	return posts, nil
+	return sched, nil
 }
 
-// parseWithAI использует Gemini для извлечения структурированных данных из текста
-func parseWithAI(post ScrapedPost, apiKey string) *models.Hackathon {
-	ctx := context.Background()
+// runOnce выполняет один проход по всем (или выбранным) каналам и
+// завершает процесс с ненулевым кодом, если хотя бы один канал упал
+func runOnce(ctx context.Context, sched *scheduler.Scheduler, sources string) {
+	failed := false
+	if sources == "" {
+		failed = sched.RunAll(ctx) != nil
+	}
 
-	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
-	if err != nil {
-		slog.Error("Ошибка инициализации Gemini", "error", err)
+	for _, source := range strings.Split(sources, ",") {
+		source = strings.TrimSpace(source)
+		if source == "" {
+			continue
+		}
+		if err := sched.RunNow(ctx, source); err != nil {
+			slog.Error("Ошибка разового запуска", "channel", source, "error", err)
+			failed = true
+		}
+	}
+
+	slog.Info("Разовый проход парсера завершен")
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// runChannelExclusive берет advisory-лок в Postgres, чтобы несколько реплик
+// парсера не обрабатывали один и тот же канал одновременно
+func runChannelExclusive(ctx context.Context, channel string) error {
+	err := database.WithAdvisoryLock(ctx, db, "scraper:"+channel, func(ctx context.Context) error {
+		return runChannel(ctx, channel)
+	})
+	if errors.Is(err, database.ErrLockNotAcquired) {
+		slog.Info("Канал уже обрабатывается другой репликой, пропускаем", "channel", channel)
 		return nil
 	}
-	defer client.Close()
+	return err
+}
 
-	model := client.GenerativeModel("gemini-2.5-flash-lite")
-	model.SetTemperature(0.1)
-	model.ResponseMIMEType = "application/json"
+// runChannel парсит один канал и сохраняет найденные хакатоны
+func runChannel(ctx context.Context, channel string) error {
+	twoMonthsAgo := time.Now().AddDate(0, -2, 0)
 
-	currentDate := time.Now().Format("2006-01-02")
-	postDate := post.PublishedAt.Format("2006-01-02")
+	slog.Info("Парсинг канала", "channel", channel)
+	posts, err := tg.FetchChannel(ctx, channel)
+	if err != nil {
+		return fmt.Errorf("ошибка парсинга канала %s: %w", channel, err)
+	}
 
-	prompt := fmt.Sprintf(`Сегодняшняя дата: %s. Пост был опубликован: %s. 
-Проанализируй текст анонса. Если дедлайн регистрации или сам хакатон уже прошли относительно сегодняшней даты, верни статус 'DEAD'. Если он еще предстоит — 'LIVE'. Вычисли точный год, опираясь на дату публикации. 
-Верни СТРОГО JSON: title (string), date_str (string, например '21-22 февраля 2024'), deadline (string 'YYYY-MM-DD', если нет - пустая строка), format (ОФЛАЙН/ОНЛАЙН), city (string/null), ageLimit (string), link (string/null), status ('LIVE' или 'DEAD').
+	slog.Info("Получены посты канала", "count", len(posts), "channel", channel)
 
-Текст анонса:
----
-%s
----
-Только чистый JSON.`, currentDate, postDate, post.Text)
+	var candidates []telegram.Post
+	for _, post := range posts {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
-	if err != nil {
-		slog.Error("Ошибка генерации ИИ", "error", err)
-		return nil
+		// Игнор старья: пропускаем посты старше 2 месяцев
+		if post.PublishedAt.Before(twoMonthsAgo) {
+			slog.Debug("Пропуск слишком старого поста", "published_at", post.PublishedAt)
+			continue
+		}
+
+		// Оставляем только анонсы хакатонов (ключевые слова + опциональная проверка ИИ)
+		verdict, err := clsf.Classify(ctx, post.Text)
+		if err != nil {
+			slog.Warn("Ошибка проверки поста через ИИ, используем скор по ключевым словам", "error", err)
+		}
+		if !verdict.Accepted {
+			slog.Debug("Пост не похож на анонс хакатона", "score", verdict.Score, "matches", verdict.Matches)
+			continue
+		}
+		slog.Debug("Пост похож на анонс хакатона", "score", verdict.Score, "matches", verdict.Matches, "llm", verdict.Verified)
+
+		post.Links = tg.ResolveRedirects(ctx, post.Links)
+		candidates = append(candidates, post)
 	}
 
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		slog.Error("Пустой ответ от Gemini")
-		return nil
+	for start := 0; start < len(candidates); start += batchSize {
+		batch := candidates[start:min(start+batchSize, len(candidates))]
+
+		// Извлечение полей через Gemini (шаблоны промптов internal/prompts/templates)
+		results, errs, err := extractPosts(ctx, batch)
+		if errors.Is(err, llmusage.ErrBudgetExceeded) {
+			// Необработанные посты останутся в ленте канала до следующего запуска
+			slog.Warn("Дневной бюджет токенов парсера исчерпан, останавливаем канал", "channel", channel, "error", err)
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			slog.Error("Ошибка извлечения полей через ИИ", "channel", channel, "posts", len(batch), "error", err)
+			continue
+		}
+
+		budgetExceeded := false
+		for i, post := range batch {
+			if errors.Is(errs[i], llmusage.ErrBudgetExceeded) {
+				budgetExceeded = true
+				continue
+			}
+			savePost(ctx, post, results[i], errs[i])
+		}
+		if budgetExceeded {
+			slog.Warn("Дневной бюджет токенов парсера исчерпан, останавливаем канал", "channel", channel)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(3 * time.Second):
+		}
 	}
 
-	jsonText := fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
+	slog.Info("Парсинг канала завершен", "channel", channel)
+	return nil
+}
 
-	jsonText = strings.TrimPrefix(jsonText, "```json\n")
-	jsonText = strings.TrimPrefix(jsonText, "```\n")
-	jsonText = strings.TrimSuffix(jsonText, "\n```")
-	jsonText = strings.TrimSpace(jsonText)
+// extractPosts извлекает поля постов одним вызовом модели, если экстрактор это
+// умеет. Если пакетный ответ не удалось разобрать, посты извлекаются по одному.
+// Исчерпанный бюджет во время добора отдельных постов попадет в errs этого и
+// всех следующих постов, а уже извлеченные посты вернутся как есть.
+// Ошибка err означает, что не обработан ни один пост.
+func extractPosts(ctx context.Context, posts []telegram.Post) ([]extract.Result, []error, error) {
+	now := time.Now()
 
-	var aiResp AIResponse
-	if err := json.Unmarshal([]byte(jsonText), &aiResp); err != nil {
-		slog.Error("Парсинг JSON провален", "error", err, "raw_json", jsonText)
-		return nil
+	if batcher, ok := extractor.(extract.BatchExtractor); ok && len(posts) > 1 {
+		results, errs, err := batcher.ExtractBatch(ctx, posts, now)
+		if err == nil {
+			// Посты, пропущенные моделью в пакетном ответе, добираем по одному
+			for i, post := range posts {
+				if errors.Is(errs[i], extract.ErrMissing) {
+					results[i], errs[i] = extractor.Extract(ctx, post, now)
+					if errors.Is(errs[i], llmusage.ErrBudgetExceeded) {
+						markBudgetExceeded(errs[i+1:], extract.ErrMissing)
+						break
+					}
+				}
+			}
+			return results, errs, nil
+		}
+		if errors.Is(err, llmusage.ErrBudgetExceeded) || ctx.Err() != nil {
+			return nil, nil, err
+		}
+		slog.Warn("Ошибка пакетного извлечения, обрабатываем посты по одному", "posts", len(posts), "error", err)
 	}
 
-	if aiResp.Title == "" || aiResp.Title == "null" {
-		slog.Warn("ИИ вернул пустой Title, пропускаем пост")
-		return nil
+	results := make([]extract.Result, len(posts))
+	errs := make([]error, len(posts))
+	for i, post := range posts {
+		results[i], errs[i] = extractor.Extract(ctx, post, now)
+		if errors.Is(errs[i], llmusage.ErrBudgetExceeded) {
+			// Уже извлеченные посты оплачены — отдаем их, остальные не трогаем
+			markBudgetExceeded(errs[i+1:], nil)
+			break
+		}
 	}
+	return results, errs, nil
+}
 
-	// Конвертация из AIResponse в models.Hackathon
-	hackathon := &models.Hackathon{
-		Title:    aiResp.Title,
-		Date:     aiResp.DateStr,
-		Format:   aiResp.Format,
-		AgeLimit: aiResp.AgeLimit,
-		Status:   aiResp.Status,
+// markBudgetExceeded помечает ErrBudgetExceeded посты, которые уже не отправить
+// модели; если only задан, то только посты с этой ошибкой
+func markBudgetExceeded(errs []error, only error) {
+	for j := range errs {
+		if only == nil || errors.Is(errs[j], only) {
+			errs[j] = llmusage.ErrBudgetExceeded
+		}
 	}
+}
 
-	if aiResp.City != nil && *aiResp.City != "null" {
-		hackathon.City = *aiResp.City
+// savePost сохраняет извлеченный из поста хакатон вместе с постером
+func savePost(ctx context.Context, post telegram.Post, extracted extract.Result, err error) {
+	if errors.Is(err, extract.ErrNoHackathon) {
+		slog.Warn("ИИ вернул пустой Title, пропускаем пост", "permalink", post.Permalink)
+		return
+	}
+	if errors.Is(err, extract.ErrUngrounded) {
+		slog.Warn("Название от ИИ не найдено в тексте поста, пропускаем", "permalink", post.Permalink, "title", extracted.Title)
+		return
 	}
-	if aiResp.Link != nil && *aiResp.Link != "null" {
-		hackathon.Link = *aiResp.Link
+	if err != nil {
+		slog.Error("Ошибка извлечения полей через ИИ", "permalink", post.Permalink, "error", err)
+		return
 	}
+	hackathon := extracted.Hackathon(locale)
 
-	if aiResp.Deadline != "" && aiResp.Deadline != "null" {
-		parsedDeadline, err := time.Parse("2006-01-02", aiResp.Deadline)
-		if err == nil {
-			hackathon.Deadline = &parsedDeadline
-		} else {
-			slog.Warn("Ошибка парсинга Deadline от ИИ", "deadline", aiResp.Deadline, "error", err)
+	// Ссылку на регистрацию выбираем по правилам; ответ ИИ — только запасной вариант
+	if link, ok := telegram.PickRegistrationLink(post.Links); ok {
+		hackathon.Link = link
+	} else if hackathon.Link == "" {
+		hackathon.Link = post.Permalink
+	}
+
+	// Канал — доверенный источник: найденный ИИ-поиском дубликат на модерации
+	// перезаписывается, а уже одобренный или отклоненный — пропускаем
+	saved, err := database.SaveTrusted(db, hackathon)
+	switch {
+	case err != nil:
+		slog.Error("Ошибка сохранения хакатона", "title", hackathon.Title, "error", err)
+	case !saved:
+		slog.Info("Хакатон уже существует, пропускаем", "title", hackathon.Title)
+	default:
+		slog.Info("✅ Успешно добавлен новый хакатон!", "title", hackathon.Title)
+		// Все города проведения (для национальных ивентов — и национальные города региона)
+		if err := database.LinkCities(db, hackathon, extracted.CitySlugs(locale)); err != nil {
+			slog.Warn("Не удалось привязать хакатон к городам", "title", hackathon.Title, "error", err)
+		}
+		// Теги, исправленные модератором, не трогаем
+		if hackathon.TagsEditedAt == nil {
+			if err := database.LinkTags(db, hackathon, extracted.TagSlugs(tags)); err != nil {
+				slog.Warn("Не удалось проставить теги хакатону", "title", hackathon.Title, "error", err)
+			}
+		}
+		if err := database.LinkOrganizer(db, hackathon, extracted.Organizer); err != nil {
+			slog.Warn("Не удалось сохранить организатора", "title", hackathon.Title, "organizer", extracted.Organizer, "error", err)
+		}
+		// Постер — первое фото поста; качаем только для новых хакатонов, чтобы
+		// дубликаты не скачивали его заново
+		if len(post.Images) > 0 {
+			savePoster(ctx, hackathon, post.Images[0])
 		}
 	}
+}
 
-	return hackathon
+// savePoster сохраняет постер и его превью и проставляет их уже сохраненному хакатону
+func savePoster(ctx context.Context, hackathon *models.Hackathon, imageURL string) {
+	img, err := mediaProc.Save(ctx, imageURL)
+	if err != nil {
+		slog.Warn("Не удалось сохранить постер", "url", imageURL, "error", err)
+		return
+	}
+	err = db.Model(hackathon).UpdateColumns(map[string]any{"image_url": img.URL, "thumbnail_url": img.ThumbnailURL}).Error
+	if err != nil {
+		slog.Warn("Не удалось сохранить ссылку на постер", "title", hackathon.Title, "error", err)
+		return
+	}
+	hackathon.ImageURL = img.URL
+	hackathon.ThumbnailURL = img.ThumbnailURL
 }
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUExpiry(t *testing.T) {
+	c := NewLRU[string, int](10, time.Hour)
+	c.Set("fresh", 1)
+	c.SetWithTTL("stale", 2, -time.Second)
+
+	if v, ok := c.Get("fresh"); !ok || v != 1 {
+		t.Errorf("Get(fresh) = %d, %v; want 1, true", v, ok)
+	}
+	if _, ok := c.Get("stale"); ok {
+		t.Error("Get(stale) found an expired entry")
+	}
+	// expired entries are dropped on access
+	if n := c.Len(); n != 1 {
+		t.Errorf("Len = %d after reading the expired entry, want 1", n)
+	}
+
+	// Set refreshes the TTL of an existing entry
+	c.SetWithTTL("fresh", 3, -time.Second)
+	c.Set("fresh", 4)
+	if v, ok := c.Get("fresh"); !ok || v != 4 {
+		t.Errorf("Get(fresh) after reset = %d, %v; want 4, true", v, ok)
+	}
+}
+
+func TestLRUExpiresWithTime(t *testing.T) {
+	c := NewLRU[string, int](10, 20*time.Millisecond)
+	c.Set("a", 1)
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get(a) found an entry past its TTL")
+	}
+}
+
+func TestLRUEviction(t *testing.T) {
+	c := NewLRU[string, int](2, time.Hour)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // b is now the least recently used
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b was not evicted")
+	}
+	for _, k := range []string{"a", "c"} {
+		if _, ok := c.Get(k); !ok {
+			t.Errorf("%s was evicted", k)
+		}
+	}
+	if n := c.Len(); n != 2 {
+		t.Errorf("Len = %d, want 2", n)
+	}
+
+	// overwriting counts as a use and does not grow the cache
+	c.Set("a", 10)
+	c.Set("d", 4)
+	if _, ok := c.Get("c"); ok {
+		t.Error("c was not evicted after a was overwritten")
+	}
+	if v, ok := c.Get("a"); !ok || v != 10 {
+		t.Errorf("Get(a) = %d, %v; want 10, true", v, ok)
+	}
+}
+
+func TestLRUDelete(t *testing.T) {
+	c := NewLRU[string, int](2, time.Hour)
+	c.Set("a", 1)
+	c.Delete("a")
+	c.Delete("missing")
+	if _, ok := c.Get("a"); ok || c.Len() != 0 {
+		t.Errorf("Get(a) after Delete = %v with %d entries, want nothing", ok, c.Len())
+	}
+}
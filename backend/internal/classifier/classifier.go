@@ -0,0 +1,96 @@
+// Package classifier decides whether a scraped post announces a hackathon-like
+// event before it is sent to the (expensive) extraction model.
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Verifier is a cheap yes/no check, typically backed by a small LLM, used for
+// posts whose keyword score is not conclusive.
+type Verifier interface {
+	Verify(ctx context.Context, text string) (bool, error)
+}
+
+// Result explains a classification decision.
+type Result struct {
+	Score    float64  `json:"score"`
+	Matches  []string `json:"matches"`
+	Accepted bool     `json:"accepted"`
+	// Verified is true when the LLM stage made the final decision.
+	Verified bool `json:"verified"`
+}
+
+type compiledRule struct {
+	set    string
+	re     *regexp.Regexp
+	weight float64
+}
+
+// Classifier scores posts with weighted keyword and negative rules.
+type Classifier struct {
+	cfg      *Config
+	rules    []compiledRule
+	verifier Verifier
+}
+
+// New compiles the rules in cfg. A nil verifier disables the LLM stage even
+// if it is enabled in the config.
+func New(cfg *Config, verifier Verifier) (*Classifier, error) {
+	c := &Classifier{cfg: cfg}
+	if cfg.LLM.Enabled {
+		c.verifier = verifier
+	}
+
+	for _, sets := range [][]RuleSet{cfg.Keywords, cfg.Negative} {
+		for _, set := range sets {
+			for _, rule := range set.Rules {
+				re, err := regexp.Compile("(?i)" + rule.Pattern)
+				if err != nil {
+					return nil, fmt.Errorf("invalid pattern %q in rule set %q: %w", rule.Pattern, set.Name, err)
+				}
+				c.rules = append(c.rules, compiledRule{set: set.Name, re: re, weight: rule.Weight})
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// Score applies only the keyword stage. Each rule contributes its weight at
+// most once, regardless of how many times it matches.
+func (c *Classifier) Score(text string) Result {
+	lower := strings.ToLower(text)
+
+	var res Result
+	for _, rule := range c.rules {
+		if m := rule.re.FindString(lower); m != "" {
+			res.Score += rule.weight
+			res.Matches = append(res.Matches, rule.set+":"+m)
+		}
+	}
+	res.Accepted = res.Score >= c.cfg.Threshold
+
+	return res
+}
+
+// Classify runs the keyword stage and, for inconclusive scores, the LLM stage.
+// If the verifier fails, the keyword decision is kept and the error returned.
+func (c *Classifier) Classify(ctx context.Context, text string) (Result, error) {
+	res := c.Score(text)
+	if c.verifier == nil || res.Score < c.cfg.LLM.MinScore || res.Score >= c.cfg.LLM.ConfidentScore {
+		return res, nil
+	}
+
+	ok, err := c.verifier.Verify(ctx, text)
+	if err != nil {
+		return res, fmt.Errorf("llm verification failed: %w", err)
+	}
+	res.Accepted = ok
+	res.Verified = true
+
+	return res, nil
+}
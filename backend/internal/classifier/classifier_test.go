@@ -0,0 +1,125 @@
+package classifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// Minimum quality of the built-in rules on the labeled corpus; raise them as
+// the rules improve, never lower them to make a change pass.
+const (
+	minPrecision = 0.9
+	minRecall    = 0.9
+)
+
+func newDefault(t *testing.T, verifier Verifier) *Classifier {
+	t.Helper()
+	cfg, err := DefaultConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.LLM.Enabled = verifier != nil
+	c, err := New(cfg, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCorpus(t *testing.T) {
+	samples, err := LoadCorpus("testdata/corpus.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := Evaluate(context.Background(), newDefault(t, nil), samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, miss := range m.Misses {
+		t.Logf("%s: label=%v score=%.2f matches=%v", miss.Sample.ID, miss.Sample.Label, miss.Result.Score, miss.Result.Matches)
+	}
+	if p := m.Precision(); p < minPrecision {
+		t.Errorf("precision = %.3f, want >= %.2f", p, minPrecision)
+	}
+	if r := m.Recall(); r < minRecall {
+		t.Errorf("recall = %.3f, want >= %.2f", r, minRecall)
+	}
+}
+
+func TestScore(t *testing.T) {
+	c := newDefault(t, nil)
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{"keyword alone reaches the threshold", "Хакатон в Алматы", true},
+		{"kk inflection matches the ru keyword", "Астана хакатонға шақырады", true},
+		{"weak signals stay below the threshold", "Регистрация открыта, призовой фонд 1 млн", false},
+		{"winners", "Поздравляем победителей хакатона!", false},
+		{"results", "Подвели итоги хакатона, спасибо всем", false},
+		{"winners en", "Congratulations to the hackathon winners", false},
+		{"vacancy", "Вакансия: ищем в команду разработчика, хакатоны — плюс", false},
+		{"hiring", "We are hiring! Hackathon experience is a plus", false},
+		{"announcement outweighs a mention of past winners", "Хакатон AI Challenge: регистрация до 1 мая, призовой фонд 5 млн. Победители прошлого года стали стартапами", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Score(tt.text); got.Accepted != tt.want {
+				t.Errorf("Score(%q) = %.2f %v, accepted %v, want %v", tt.text, got.Score, got.Matches, got.Accepted, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreCountsRuleOnce(t *testing.T) {
+	c := newDefault(t, nil)
+	if got := c.Score("хакатон хакатон хакатон").Score; got != 1.0 {
+		t.Errorf("Score = %.2f, want 1.00", got)
+	}
+}
+
+type stubVerifier struct {
+	answer bool
+	err    error
+	calls  int
+}
+
+func (v *stubVerifier) Verify(context.Context, string) (bool, error) {
+	v.calls++
+	return v.answer, v.err
+}
+
+func TestClassifyVerifier(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		verifier  *stubVerifier
+		wantCalls int
+		want      bool
+		wantErr   bool
+	}{
+		// score 0.9 and 1.3: inconclusive, the model decides
+		{"inconclusive accepted by model", "Тіркелу ашық, жүлде қоры бар, регистрация", &stubVerifier{answer: true}, 1, true, false},
+		{"inconclusive rejected by model", "Хакатон, регистрация открыта", &stubVerifier{answer: false}, 1, false, false},
+		{"verifier error keeps keyword decision", "Хакатон, регистрация открыта", &stubVerifier{err: errors.New("quota")}, 1, true, true},
+		// score 2.6: confident, the model is not asked
+		{"confident", "Хакатон! Регистрация, призовой фонд, hackathon", &stubVerifier{answer: false}, 0, true, false},
+		// score 0.4 and 0: below minScore, the model is not asked
+		{"negatives below minScore", "Хакатон: подвели итоги", &stubVerifier{answer: true}, 0, false, false},
+		{"irrelevant", "Митап Go-разработчиков", &stubVerifier{answer: true}, 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newDefault(t, tt.verifier)
+			res, err := c.Classify(context.Background(), tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Classify error = %v, want error %v", err, tt.wantErr)
+			}
+			if res.Accepted != tt.want || tt.verifier.calls != tt.wantCalls {
+				t.Errorf("Classify = %+v after %d verifier calls, want accepted %v after %d", res, tt.verifier.calls, tt.want, tt.wantCalls)
+			}
+		})
+	}
+}
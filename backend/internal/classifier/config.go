@@ -0,0 +1,67 @@
+package classifier
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+//go:embed default.json
+var defaultConfig []byte
+
+// Rule is a weighted case-insensitive regular expression. Positive weights
+// vote for a hackathon announcement, negative weights against it.
+type Rule struct {
+	Pattern string  `json:"pattern"`
+	Weight  float64 `json:"weight"`
+}
+
+// RuleSet groups rules by language or purpose so they can be tuned together.
+type RuleSet struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules"`
+}
+
+// LLMConfig controls the optional yes/no verification stage. Only posts
+// scoring in [MinScore, ConfidentScore) are sent to the model.
+type LLMConfig struct {
+	Enabled        bool    `json:"enabled"`
+	MinScore       float64 `json:"minScore"`
+	ConfidentScore float64 `json:"confidentScore"`
+}
+
+// Config is the full classifier configuration.
+type Config struct {
+	Threshold float64   `json:"threshold"`
+	Keywords  []RuleSet `json:"keywords"`
+	Negative  []RuleSet `json:"negative"`
+	LLM       LLMConfig `json:"llm"`
+}
+
+// DefaultConfig returns the built-in configuration.
+func DefaultConfig() (*Config, error) {
+	return parseConfig(defaultConfig)
+}
+
+// LoadConfig reads a JSON configuration file. An empty path yields the
+// built-in defaults.
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		return DefaultConfig()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read classifier config: %w", err)
+	}
+	return parseConfig(data)
+}
+
+func parseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse classifier config: %w", err)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,109 @@
+package classifier
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Sample is a labeled post from the evaluation corpus.
+type Sample struct {
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+	Label bool   `json:"label"`
+}
+
+// Miss is a misclassified sample.
+type Miss struct {
+	Sample Sample
+	Result Result
+}
+
+// Metrics summarizes classifier quality over a corpus.
+type Metrics struct {
+	TruePositives  int
+	FalsePositives int
+	TrueNegatives  int
+	FalseNegatives int
+	Misses         []Miss
+}
+
+// Precision is TP / (TP + FP).
+func (m Metrics) Precision() float64 {
+	return ratio(m.TruePositives, m.TruePositives+m.FalsePositives)
+}
+
+// Recall is TP / (TP + FN).
+func (m Metrics) Recall() float64 {
+	return ratio(m.TruePositives, m.TruePositives+m.FalseNegatives)
+}
+
+// F1 is the harmonic mean of precision and recall.
+func (m Metrics) F1() float64 {
+	p, r := m.Precision(), m.Recall()
+	if p+r == 0 {
+		return 0
+	}
+	return 2 * p * r / (p + r)
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// LoadCorpus reads a JSON Lines file of samples. Blank lines and lines
+// starting with "#" are ignored.
+func LoadCorpus(path string) ([]Sample, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var samples []Sample
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" || strings.HasPrefix(raw, "#") {
+			continue
+		}
+		var s Sample
+		if err := json.Unmarshal([]byte(raw), &s); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		samples = append(samples, s)
+	}
+	return samples, scanner.Err()
+}
+
+// Evaluate classifies every sample and compares the decision with its label.
+func Evaluate(ctx context.Context, c *Classifier, samples []Sample) (Metrics, error) {
+	var m Metrics
+	for _, s := range samples {
+		res, err := c.Classify(ctx, s.Text)
+		if err != nil {
+			return m, fmt.Errorf("sample %s: %w", s.ID, err)
+		}
+
+		switch {
+		case res.Accepted && s.Label:
+			m.TruePositives++
+		case res.Accepted && !s.Label:
+			m.FalsePositives++
+			m.Misses = append(m.Misses, Miss{Sample: s, Result: res})
+		case !res.Accepted && s.Label:
+			m.FalseNegatives++
+			m.Misses = append(m.Misses, Miss{Sample: s, Result: res})
+		default:
+			m.TrueNegatives++
+		}
+	}
+	return m, nil
+}
@@ -3,6 +3,9 @@ package config
 import (
 	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -18,6 +21,68 @@ type Config struct {
 	DBPort       string
 	TavilyAPIKey string
 	GeminiAPIKey string
+	AdminToken   string
+	MediaDir     string
+	MediaBaseURL string
+
+	// Outbound HTTP settings
+	HTTPTimeout        time.Duration
+	HTTPAttemptTimeout time.Duration
+	HTTPMaxRetries     int
+	HTTPUserAgent      string
+	HTTPRecordMode     string
+	HTTPCassetteDir    string
+	TavilyBaseURL      string
+	TelegramBaseURL    string
+
+	// Overall deadlines for API endpoints
+	SearchTimeout       time.Duration
+	StreamSearchTimeout time.Duration
+
+	// Web search provider for the AI agent: tavily, searxng or static
+	WebSearchProvider string
+	SearXNGBaseURL    string
+	WebSearchFixture  string
+
+	// Default region and output language of AI extraction; REGIONS_CONFIG
+	// overrides the built-in region definitions
+	Region        string
+	Language      string
+	RegionsConfig string
+	// TagsConfig overrides the built-in topic tag taxonomy
+	TagsConfig string
+
+	// Full page fetching for the top web search results (0 disables)
+	PageFetchTopN        int
+	PageFetchTokenBudget int
+	PageFetchCacheTTL    time.Duration
+
+	// AI search response cache (size 0 disables); SearchCachePersist also
+	// stores entries in Postgres so they survive restarts
+	SearchCacheSize    int
+	SearchCacheTTL     time.Duration
+	SearchCachePersist bool
+
+	// Daily LLM token budgets per caller (0 means unlimited). Once the search
+	// budget is used up, AI search answers from the database only
+	LLMBudgetSearch     int
+	LLMBudgetScraper    int
+	LLMBudgetClassifier int
+
+	// HybridMinResults is the number of database hits below which hybrid
+	// search also queries the web agent.
+	HybridMinResults int
+
+	// Scraper settings
+	ScraperChannels        []string
+	ScraperSchedule        string
+	ScraperSourceSchedules map[string]string
+	ScraperJitter          time.Duration
+	ScraperAdminPort       string
+	ClassifierConfig       string
+	// ExtractBatchSize is the number of posts extracted with one LLM call
+	// (1 disables batching)
+	ExtractBatchSize int
 }
 
 // Load reads the application configuration from environment variables
@@ -39,11 +104,69 @@ func Load() *Config {
 		DBPort:       getEnvOrDefault("DB_PORT", "5432"),
 		TavilyAPIKey: os.Getenv("TAVILY_API_KEY"),
 		GeminiAPIKey: os.Getenv("GEMINI_API_KEY"),
+		AdminToken:   os.Getenv("ADMIN_TOKEN"),
+		MediaDir:     getEnvOrDefault("MEDIA_DIR", "./media"),
+		MediaBaseURL: getEnvOrDefault("MEDIA_BASE_URL", "http://localhost:8080/media"),
+
+		HTTPTimeout:        getDurationOrDefault("HTTP_TIMEOUT", 30*time.Second),
+		HTTPAttemptTimeout: getDurationOrDefault("HTTP_ATTEMPT_TIMEOUT", 10*time.Second),
+		HTTPMaxRetries:     getIntOrDefault("HTTP_MAX_RETRIES", 3),
+		HTTPUserAgent:      os.Getenv("HTTP_USER_AGENT"),
+		HTTPRecordMode:     os.Getenv("HTTP_RECORD_MODE"),
+		HTTPCassetteDir:    os.Getenv("HTTP_CASSETTE_DIR"),
+		TavilyBaseURL:      getEnvOrDefault("TAVILY_BASE_URL", "https://api.tavily.com"),
+		TelegramBaseURL:    getEnvOrDefault("TELEGRAM_BASE_URL", "https://t.me"),
+
+		SearchTimeout:       getDurationOrDefault("SEARCH_TIMEOUT", 45*time.Second),
+		StreamSearchTimeout: getDurationOrDefault("STREAM_SEARCH_TIMEOUT", 90*time.Second),
+
+		WebSearchProvider: getEnvOrDefault("WEB_SEARCH_PROVIDER", "tavily"),
+		SearXNGBaseURL:    getEnvOrDefault("SEARXNG_BASE_URL", "http://localhost:8888"),
+		WebSearchFixture:  getEnvOrDefault("WEB_SEARCH_FIXTURE", "testdata/websearch/hackathons.json"),
+		HybridMinResults:  getIntOrDefault("HYBRID_MIN_RESULTS", 3),
+
+		Region:        getEnvOrDefault("REGION", "kz"),
+		Language:      os.Getenv("OUTPUT_LANGUAGE"),
+		RegionsConfig: os.Getenv("REGIONS_CONFIG"),
+		TagsConfig:    os.Getenv("TAGS_CONFIG"),
+
+		PageFetchTopN:        getIntOrDefault("PAGE_FETCH_TOP_N", 3),
+		PageFetchTokenBudget: getIntOrDefault("PAGE_FETCH_TOKEN_BUDGET", 6000),
+		PageFetchCacheTTL:    getDurationOrDefault("PAGE_FETCH_CACHE_TTL", 6*time.Hour),
+
+		SearchCacheSize:    getIntOrDefault("SEARCH_CACHE_SIZE", 500),
+		SearchCacheTTL:     getDurationOrDefault("SEARCH_CACHE_TTL", 6*time.Hour),
+		SearchCachePersist: getBoolOrDefault("SEARCH_CACHE_PERSIST", false),
+
+		LLMBudgetSearch:     getIntOrDefault("LLM_BUDGET_SEARCH", 0),
+		LLMBudgetScraper:    getIntOrDefault("LLM_BUDGET_SCRAPER", 0),
+		LLMBudgetClassifier: getIntOrDefault("LLM_BUDGET_CLASSIFIER", 0),
+
+		ScraperChannels: getListOrDefault("SCRAPER_CHANNELS", []string{
+			"astanahub", "uppertunity", "nuris_nu", "terriconvalley",
+			"bluescreenkz", "kolesa_team", "tce_kz", "hackathons_ru",
+		}),
+		ScraperSchedule:        getEnvOrDefault("SCRAPER_SCHEDULE", "0 */6 * * *"),
+		ScraperSourceSchedules: getPrefixedEnv("SCRAPER_SCHEDULE_"),
+		ScraperJitter:          getDurationOrDefault("SCRAPER_JITTER", 5*time.Minute),
+		ScraperAdminPort:       os.Getenv("SCRAPER_ADMIN_PORT"),
+		ClassifierConfig:       os.Getenv("CLASSIFIER_CONFIG"),
+		ExtractBatchSize:       getIntOrDefault("EXTRACT_BATCH_SIZE", 5),
 	}
 
 	return cfg
 }
 
+// SourceSchedule returns the cron expression for a scraper source, falling
+// back to the global ScraperSchedule. Per-source overrides are set with
+// SCRAPER_SCHEDULE_<SOURCE>, e.g. SCRAPER_SCHEDULE_ASTANAHUB="*/30 * * * *".
+func (c *Config) SourceSchedule(source string) string {
+	if expr, ok := c.ScraperSourceSchedules[strings.ToUpper(source)]; ok {
+		return expr
+	}
+	return c.ScraperSchedule
+}
+
 func getEnvOrDefault(key, fallback string) string {
 	value, exists := os.LookupEnv(key)
 	if !exists || value == "" {
@@ -51,3 +174,74 @@ func getEnvOrDefault(key, fallback string) string {
 	}
 	return value
 }
+
+func getListOrDefault(key string, fallback []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func getIntOrDefault(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Warn("Invalid integer in environment, using default", "key", key, "value", value, "default", fallback)
+		return fallback
+	}
+	return n
+}
+
+func getBoolOrDefault(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		slog.Warn("Invalid boolean in environment, using default", "key", key, "value", value, "default", fallback)
+		return fallback
+	}
+	return b
+}
+
+func getDurationOrDefault(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Warn("Invalid duration in environment, using default", "key", key, "value", value, "default", fallback)
+		return fallback
+	}
+	return d
+}
+
+// getPrefixedEnv collects all non-empty variables starting with prefix,
+// keyed by the remainder of the variable name.
+func getPrefixedEnv(prefix string) map[string]string {
+	values := make(map[string]string)
+	for _, kv := range os.Environ() {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || value == "" || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		values[strings.TrimPrefix(key, prefix)] = value
+	}
+	return values
+}
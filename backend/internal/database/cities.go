@@ -0,0 +1,113 @@
+package database
+
+import (
+	"fmt"
+	"log/slog"
+
+	"hackflow-api/internal/models"
+	"hackflow-api/internal/region"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SyncCities upserts the cities of the region configuration into the cities
+// table and, once, links hackathons stored with only a free-text city to
+// their cities.
+func SyncCities(db *gorm.DB, regions *region.Registry) error {
+	var cities []models.City
+	for _, reg := range regions.Regions() {
+		for _, c := range reg.Cities {
+			cities = append(cities, models.City{
+				Slug:    c.Slug,
+				Region:  reg.Code,
+				Names:   c.Names,
+				Aliases: c.Aliases,
+				Lat:     c.Lat,
+				Lon:     c.Lon,
+			})
+		}
+	}
+	if len(cities) > 0 {
+		err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "slug"}},
+			DoUpdates: clause.AssignmentColumns([]string{"region", "names", "aliases", "lat", "lon", "updated_at"}),
+		}).Create(&cities).Error
+		if err != nil {
+			return fmt.Errorf("failed to seed cities: %w", err)
+		}
+	}
+
+	return runOnce(db, "city-backfill", func(tx *gorm.DB) error {
+		return backfillCityLinks(tx, regions)
+	})
+}
+
+// FindCities loads the cities with the given slugs, in the order of slugs.
+// Unknown slugs are skipped.
+func FindCities(db *gorm.DB, slugs []string) ([]models.City, error) {
+	if len(slugs) == 0 {
+		return nil, nil
+	}
+
+	var found []models.City
+	if err := db.Where("slug IN ?", slugs).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]models.City, len(found))
+	for _, c := range found {
+		bySlug[c.Slug] = c
+	}
+
+	cities := make([]models.City, 0, len(found))
+	for _, slug := range slugs {
+		if c, ok := bySlug[slug]; ok {
+			cities = append(cities, c)
+		}
+	}
+	return cities, nil
+}
+
+// LinkCities adds the cities with the given slugs to a stored hackathon.
+// Link after the insert succeeded: an insert skipped by ON CONFLICT DO NOTHING
+// leaves the hackathon without an ID.
+func LinkCities(db *gorm.DB, h *models.Hackathon, slugs []string) error {
+	cities, err := FindCities(db, slugs)
+	if err != nil || len(cities) == 0 {
+		return err
+	}
+	return db.Model(h).Association("Cities").Append(cities)
+}
+
+// backfillCityLinks links hackathons created before events could have
+// several cities, resolving their free-text city in the default region.
+// Unknown cities are left unlinked.
+func backfillCityLinks(db *gorm.DB, regions *region.Registry) error {
+	loc, err := regions.Resolve("", "")
+	if err != nil {
+		return err
+	}
+
+	var legacy []models.Hackathon
+	linked := 0
+	res := db.Where("city <> '' AND NOT EXISTS (SELECT 1 FROM hackathon_cities hc WHERE hc.hackathon_id = hackathons.id)").
+		FindInBatches(&legacy, 500, func(*gorm.DB, int) error {
+			for _, h := range legacy {
+				slugs := loc.CitySlugs([]string{h.City}, h.National)
+				if len(slugs) == 0 {
+					continue
+				}
+				if err := LinkCities(db, &h, slugs); err != nil {
+					return fmt.Errorf("failed to link hackathon %d to cities: %w", h.ID, err)
+				}
+				linked++
+			}
+			return nil
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	slog.Info("Hackathons linked to cities", "rows", linked)
+	return nil
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 
@@ -16,7 +17,8 @@ func Init(cfg *config.Config) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
 		cfg.DBHost, cfg.DBUser, cfg.DBPass, cfg.DBName, cfg.DBPort)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// TranslateError maps unique violations to gorm.ErrDuplicatedKey
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		slog.Error("Failed to connect to database", "error", err, "host", cfg.DBHost)
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
@@ -25,12 +27,127 @@ func Init(cfg *config.Config) (*gorm.DB, error) {
 	slog.Info("Successfully connected to PostgreSQL", "host", cfg.DBHost, "db", cfg.DBName)
 
 	slog.Info("Running auto-migrations")
-	err = db.AutoMigrate(&models.Hackathon{})
+	err = db.AutoMigrate(&models.Hackathon{}, &models.SearchCacheEntry{}, &models.LLMUsage{}, &models.City{}, &models.Organizer{}, &models.Tag{}, &migration{})
 	if err != nil {
 		slog.Error("Failed to run migrations", "error", err)
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	if err := backfillDedupKeys(db); err != nil {
+		slog.Error("Failed to backfill dedup keys", "error", err)
+		return nil, fmt.Errorf("failed to backfill dedup keys: %w", err)
+	}
+
+	if err := backfillOnline(db); err != nil {
+		slog.Error("Failed to backfill online flags", "error", err)
+		return nil, fmt.Errorf("failed to backfill online flags: %w", err)
+	}
+
+	if err := backfillFormats(db); err != nil {
+		slog.Error("Failed to backfill formats and age limits", "error", err)
+		return nil, fmt.Errorf("failed to backfill formats and age limits: %w", err)
+	}
+
 	slog.Info("Database schema synchronized")
 	return db, nil
 }
+
+// backfillDedupKeys fills dedup_key for rows created before the column existed.
+// Rows whose key collides with an existing one are legacy duplicates; they keep
+// an empty key (excluded from the unique index) and are only logged.
+func backfillDedupKeys(db *gorm.DB) error {
+	var legacy []models.Hackathon
+	if err := db.Where("dedup_key = ''").Find(&legacy).Error; err != nil {
+		return err
+	}
+
+	for _, h := range legacy {
+		key := models.NormalizeTitle(h.Title)
+		if key == "" {
+			continue
+		}
+		if err := db.Model(&h).UpdateColumn("dedup_key", key).Error; err != nil {
+			slog.Warn("Skipping duplicate hackathon during dedup backfill", "id", h.ID, "title", h.Title, "error", err)
+		}
+	}
+
+	if len(legacy) > 0 {
+		slog.Info("Dedup keys backfilled", "rows", len(legacy))
+	}
+	return nil
+}
+
+// backfillOnline sets the online flag of rows created before the column
+// existed; BeforeSave keeps it in sync with Format for newer rows. Legacy
+// free-text formats ("ОФЛАЙН/ОНЛАЙН") are matched as well.
+func backfillOnline(db *gorm.DB) error {
+	res := db.Model(&models.Hackathon{}).
+		Where("NOT online").
+		Where("format IN ? OR LOWER(format) LIKE ? OR LOWER(format) LIKE ?",
+			[]models.Format{models.FormatOnline, models.FormatHybrid}, "%онлайн%", "%online%").
+		UpdateColumn("online", true)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		slog.Info("Online flags backfilled", "rows", res.RowsAffected)
+	}
+	return nil
+}
+
+// backfillFormats converts rows written before Format became an enum
+// ("ОФЛАЙН", "ОФЛАЙН/ОНЛАЙН") and, once, parses the legacy free-text
+// age_limit column into MinAge, MaxAge and Audiences. Nothing writes
+// age_limit any more, but the column is kept so binaries that still read it
+// keep working; it is to be dropped by a later migration.
+func backfillFormats(db *gorm.DB) error {
+	var legacy []models.Hackathon
+	err := db.Select("id", "format").
+		Where("format NOT IN ?", []models.Format{models.FormatOffline, models.FormatOnline, models.FormatHybrid, ""}).
+		Find(&legacy).Error
+	if err != nil {
+		return err
+	}
+	for _, h := range legacy {
+		format := models.ParseFormat(string(h.Format))
+		err := db.Model(&models.Hackathon{}).Where("id = ?", h.ID).
+			UpdateColumn("format", format).Error
+		if err != nil {
+			return err
+		}
+	}
+	if len(legacy) > 0 {
+		slog.Info("Hackathon formats backfilled", "rows", len(legacy))
+	}
+
+	return runOnce(db, "parse-age-limits", backfillAgeLimits)
+}
+
+func backfillAgeLimits(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.Hackathon{}, "age_limit") {
+		return nil
+	}
+	var ages []struct {
+		ID       uint
+		AgeLimit string
+	}
+	err := db.Model(&models.Hackathon{}).Select("id", "COALESCE(age_limit, '') AS age_limit").
+		Where("age_limit <> ''").Scan(&ages).Error
+	if err != nil {
+		return err
+	}
+	for _, a := range ages {
+		minAge, maxAge, audiences := models.ParseAgeLimit(a.AgeLimit)
+		set, err := json.Marshal(audiences)
+		if err != nil {
+			return err
+		}
+		err = db.Model(&models.Hackathon{}).Where("id = ?", a.ID).
+			UpdateColumns(map[string]any{"min_age": minAge, "max_age": maxAge, "audiences": gorm.Expr("?::jsonb", string(set))}).Error
+		if err != nil {
+			return err
+		}
+	}
+	slog.Info("Age limits backfilled", "rows", len(ages))
+	return nil
+}
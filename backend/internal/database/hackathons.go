@@ -0,0 +1,43 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"hackflow-api/internal/models"
+)
+
+// SaveTrusted inserts a hackathon from a trusted source (a monitored
+// channel). The dedup_key unique index guards against races between replicas.
+// A pending duplicate — an AI search result — does not block the insert: it
+// is overwritten and thereby approved, and its cities and tags are cleared so
+// the caller links its own. Tags a moderator edited are kept, and
+// h.TagsEditedAt is loaded so the caller can leave them alone. It reports
+// false if an approved or rejected duplicate already exists.
+func SaveTrusted(db *gorm.DB, h *models.Hackathon) (bool, error) {
+	res := db.Omit("TagsEditedAt").Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "dedup_key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Neq{Column: "dedup_key", Value: ""}}},
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "hackathons.moderation_status NOT IN ?", Vars: []any{[]string{models.ModerationApproved, models.ModerationRejected}}},
+		}},
+		UpdateAll: true,
+	}).Create(h)
+	if res.Error != nil || res.RowsAffected == 0 {
+		return false, res.Error
+	}
+
+	var stored models.Hackathon
+	if err := db.Select("tags_edited_at").Take(&stored, h.ID).Error; err != nil {
+		return true, err
+	}
+	h.TagsEditedAt = stored.TagsEditedAt
+
+	if err := db.Model(h).Association("Cities").Clear(); err != nil {
+		return true, err
+	}
+	if h.TagsEditedAt != nil {
+		return true, nil
+	}
+	return true, db.Model(h).Association("Tags").Clear()
+}
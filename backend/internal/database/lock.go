@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+// ErrLockNotAcquired is returned by WithAdvisoryLock when another session
+// already holds the lock.
+var ErrLockNotAcquired = errors.New("advisory lock is held by another session")
+
+// WithAdvisoryLock runs fn while holding a session-level PostgreSQL advisory
+// lock derived from key. The lock lives on a dedicated pooled connection, so
+// it is released automatically if the process dies mid-run.
+func WithAdvisoryLock(ctx context.Context, db *gorm.DB, key string, fn func(ctx context.Context) error) error {
+	return db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		var acquired bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(hashtext(?))", key).Scan(&acquired).Error; err != nil {
+			return fmt.Errorf("failed to acquire advisory lock %q: %w", key, err)
+		}
+		if !acquired {
+			return ErrLockNotAcquired
+		}
+
+		defer func() {
+			// Unlock even if ctx was cancelled while fn was running.
+			if err := conn.WithContext(context.Background()).Exec("SELECT pg_advisory_unlock(hashtext(?))", key).Error; err != nil {
+				slog.Error("Failed to release advisory lock", "key", key, "error", err)
+			}
+		}()
+
+		return fn(ctx)
+	})
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migration records a one-time data migration that has been applied.
+type migration struct {
+	Name      string `gorm:"primaryKey"`
+	AppliedAt time.Time
+}
+
+func (migration) TableName() string { return "schema_migrations" }
+
+// runOnce applies the data migration fn unless it is recorded as applied.
+// fn and the record share a transaction, and concurrent starts of the API and
+// the scraper wait for each other on an advisory lock, so a migration runs
+// exactly once.
+func runOnce(db *gorm.DB, name string, fn func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "migration:"+name).Error; err != nil {
+			return fmt.Errorf("failed to lock migration %s: %w", name, err)
+		}
+
+		err := tx.First(&migration{}, "name = ?", name).Error
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := fn(tx); err != nil {
+			return fmt.Errorf("migration %s: %w", name, err)
+		}
+		slog.Info("Data migration applied", "name", name)
+		return tx.Create(&migration{Name: name, AppliedAt: time.Now()}).Error
+	})
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"strings"
+
+	"hackflow-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FindOrCreateOrganizer returns the organizer with the normalized name of
+// name, creating it under name if it is new. A blank name yields nil.
+func FindOrCreateOrganizer(db *gorm.DB, name string) (*models.Organizer, error) {
+	name = strings.TrimSpace(name)
+	key := models.NormalizeTitle(name)
+	if key == "" {
+		return nil, nil
+	}
+
+	org := models.Organizer{Name: name, Key: key}
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoNothing: true,
+	}).Create(&org).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Where("key = ?", key).First(&org).Error; err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// LinkOrganizer sets the organizer of a stored hackathon by name. Like
+// LinkCities, call it after the insert succeeded.
+func LinkOrganizer(db *gorm.DB, h *models.Hackathon, name string) error {
+	org, err := FindOrCreateOrganizer(db, name)
+	if err != nil || org == nil {
+		return err
+	}
+	if err := db.Model(h).UpdateColumn("organizer_id", org.ID).Error; err != nil {
+		return err
+	}
+	h.OrganizerID = &org.ID
+	h.Organizer = org
+	return nil
+}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"fmt"
+	"log/slog"
+
+	"hackflow-api/internal/models"
+	"hackflow-api/internal/taxonomy"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SyncTags upserts the tags of the taxonomy into the tags table and, once,
+// tags hackathons stored before tagging existed.
+func SyncTags(db *gorm.DB, tax *taxonomy.Taxonomy) error {
+	var tags []models.Tag
+	for _, t := range tax.Tags() {
+		tags = append(tags, models.Tag{Slug: t.Slug, Names: t.Names})
+	}
+	if len(tags) > 0 {
+		err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "slug"}},
+			DoUpdates: clause.AssignmentColumns([]string{"names", "updated_at"}),
+		}).Create(&tags).Error
+		if err != nil {
+			return fmt.Errorf("failed to seed tags: %w", err)
+		}
+	}
+
+	return runOnce(db, "tag-backfill", func(tx *gorm.DB) error {
+		return backfillTags(tx, tax)
+	})
+}
+
+// FindTags loads the tags with the given slugs, in the order of slugs.
+// Unknown slugs are skipped.
+func FindTags(db *gorm.DB, slugs []string) ([]models.Tag, error) {
+	if len(slugs) == 0 {
+		return nil, nil
+	}
+
+	var found []models.Tag
+	if err := db.Where("slug IN ?", slugs).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]models.Tag, len(found))
+	for _, t := range found {
+		bySlug[t.Slug] = t
+	}
+
+	tags := make([]models.Tag, 0, len(found))
+	for _, slug := range slugs {
+		if t, ok := bySlug[slug]; ok {
+			tags = append(tags, t)
+		}
+	}
+	return tags, nil
+}
+
+// LinkTags adds the tags with the given slugs to a stored hackathon. Like
+// LinkCities, call it after the insert succeeded.
+func LinkTags(db *gorm.DB, h *models.Hackathon, slugs []string) error {
+	tags, err := FindTags(db, slugs)
+	if err != nil || len(tags) == 0 {
+		return err
+	}
+	return db.Model(h).Association("Tags").Append(tags)
+}
+
+// backfillTags tags hackathons that have no tags yet by keyword matching on
+// their title and tracks, in batches. Hackathons whose tags a moderator
+// edited are skipped, even when the moderator removed every tag.
+func backfillTags(db *gorm.DB, tax *taxonomy.Taxonomy) error {
+	var legacy []models.Hackathon
+	tagged := 0
+	res := db.Where("tags_edited_at IS NULL AND NOT EXISTS (SELECT 1 FROM hackathon_tags ht WHERE ht.hackathon_id = hackathons.id)").
+		FindInBatches(&legacy, 500, func(*gorm.DB, int) error {
+			for _, h := range legacy {
+				slugs := tax.Match(append([]string{h.Title}, h.Tracks...)...)
+				if len(slugs) == 0 {
+					continue
+				}
+				if err := LinkTags(db, &h, slugs); err != nil {
+					return fmt.Errorf("failed to tag hackathon %d: %w", h.ID, err)
+				}
+				tagged++
+			}
+			return nil
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	slog.Info("Hackathons tagged", "rows", tagged)
+	return nil
+}
@@ -0,0 +1,91 @@
+package extract
+
+import (
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"hackflow-api/internal/guard"
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/telegram"
+)
+
+// ErrUngrounded is returned when the extracted title does not occur in the
+// post, i.e. the model made it up or followed instructions planted in the text.
+var ErrUngrounded = errors.New("extracted title not found in post")
+
+// yearRe matches years, which posts often omit ("22–23 марта") and the model
+// is asked to infer from the publication date.
+var yearRe = regexp.MustCompile(`(19|20)\d{2}`)
+
+// sanitizePost removes instruction-like lines from the post text and link
+// captions before they reach the prompt.
+func sanitizePost(post telegram.Post) telegram.Post {
+	text, found := guard.Sanitize(post.Text)
+	post.Text = text
+
+	links := make([]telegram.Link, len(post.Links))
+	for i, l := range post.Links {
+		caption, inCaption := guard.Sanitize(l.Text)
+		l.Text = caption
+		links[i] = l
+		found = append(found, inCaption...)
+	}
+	post.Links = links
+
+	if len(found) > 0 {
+		slog.Warn("Prompt injection attempt in post", "permalink", post.Permalink, "patterns", found)
+	}
+	return post
+}
+
+// Check validates the model output against the post it was extracted from.
+// The title is checked through its verbatim copy, since Title itself may be
+// translated; a Title that is neither in the post nor a plausible translation
+// is replaced by that copy (see guard.GroundTitle). An ungrounded title
+// rejects the whole result; other ungrounded values are
+// cleared rather than trusted: the date becomes "dates TBA", the deadline,
+// link, prize pool, fee, team sizes and organizer are dropped. A LIVE status
+// with a past deadline becomes DEAD.
+func Check(resp *Response, post telegram.Post, today time.Time, loc region.Locale) error {
+	title := resp.SourceTitle
+	if strings.TrimSpace(title) == "" {
+		title = resp.Title
+	}
+	if !guard.TitleGrounded(title, post.Text) {
+		return ErrUngrounded
+	}
+	if grounded := guard.GroundTitle(resp.Title, resp.SourceTitle, post.Text, loc.Lang); grounded != resp.Title {
+		slog.Debug("Replacing title not found in post", "title", resp.Title, "source_title", resp.SourceTitle)
+		resp.Title = grounded
+	}
+
+	if !guard.DateGrounded(yearRe.ReplaceAllString(resp.DateStr, ""), post.Text) {
+		slog.Debug("Dropping date not found in post", "title", resp.Title, "date", resp.DateStr)
+		resp.DateStr = loc.Language.DatesTBD
+	}
+	if resp.Deadline != "" && !guard.DeadlineGrounded(resp.Deadline, post.Text) {
+		slog.Debug("Dropping deadline not found in post", "title", resp.Title, "deadline", resp.Deadline)
+		resp.Deadline = ""
+	}
+
+	if resp.Link != nil && strings.TrimSpace(*resp.Link) != "" {
+		urls := make([]string, 0, len(post.Links))
+		for _, l := range post.Links {
+			urls = append(urls, l.URL)
+		}
+		if !guard.LinkGrounded(*resp.Link, post.Text, urls) {
+			slog.Debug("Dropping link not found in post", "title", resp.Title, "link", *resp.Link)
+			resp.Link = nil
+		}
+	}
+
+	if dropped := guard.GroundDetails(&resp.EventDetails, &resp.Organizer, post.Text); len(dropped) > 0 {
+		slog.Debug("Dropping details not found in post", "title", resp.Title, "fields", dropped)
+	}
+
+	resp.Status = guard.StatusForDeadline(resp.Status, resp.Deadline, today)
+	return nil
+}
@@ -0,0 +1,80 @@
+package extract
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/telegram"
+)
+
+func TestCheckTranslatedTitle(t *testing.T) {
+	regions, err := region.Load("", "kz", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := telegram.Post{Text: "Открыт приём заявок на Цифровой хакатон Алматы! 5-6 апреля, Terricon Valley."}
+	today := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		lang  string
+		resp  Response
+		want  error
+		title string
+	}{
+		{"verbatim copy grounds translation", "en", Response{Title: "Almaty Digital Hackathon", SourceTitle: "Цифровой хакатон Алматы"}, nil, "Almaty Digital Hackathon"},
+		{"untranslated title", "en", Response{Title: "Цифровой хакатон Алматы"}, nil, "Цифровой хакатон Алматы"},
+		{"translation without copy", "en", Response{Title: "Almaty Digital Hackathon"}, ErrUngrounded, ""},
+		{"made-up copy", "en", Response{Title: "Free iPhone Giveaway", SourceTitle: "Free iPhone Giveaway"}, ErrUngrounded, ""},
+		{"rewritten title in the post language", "ru", Response{Title: "Бесплатные айфоны", SourceTitle: "Цифровой хакатон Алматы"}, nil, "Цифровой хакатон Алматы"},
+		{"title in another language than asked", "ru", Response{Title: "Free iPhone Giveaway", SourceTitle: "Цифровой хакатон Алматы"}, nil, "Цифровой хакатон Алматы"},
+		{"translation with a made-up year", "en", Response{Title: "Almaty Digital Hackathon 2030", SourceTitle: "Цифровой хакатон Алматы"}, nil, "Цифровой хакатон Алматы"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := regions.Resolve("", tt.lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp := tt.resp
+			if err := Check(&resp, post, today, loc); !errors.Is(err, tt.want) {
+				t.Errorf("Check = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && resp.Title != tt.title {
+				t.Errorf("Check title = %q, want %q", resp.Title, tt.title)
+			}
+		})
+	}
+}
+
+func TestCheckDetails(t *testing.T) {
+	regions, err := region.Load("", "kz", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, err := regions.Resolve("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := telegram.Post{Text: "Цифровой хакатон Алматы от Terricon Valley. Призовой фонд 3 млн тенге!"}
+	today := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	prize, fee := int64(3000000), int64(10000)
+	resp := Response{Title: "Цифровой хакатон Алматы", Organizer: "Google"}
+	resp.PrizePool, resp.PrizeCurrency = &prize, "KZT"
+	resp.Fee, resp.FeeCurrency = &fee, "KZT"
+	if err := Check(&resp, post, today, loc); err != nil {
+		t.Fatal(err)
+	}
+	if resp.PrizePool == nil || *resp.PrizePool != prize {
+		t.Errorf("PrizePool = %v, want %d", resp.PrizePool, prize)
+	}
+	if resp.Fee != nil || resp.FeeCurrency != "" {
+		t.Errorf("Fee = %v %q, want it dropped", resp.Fee, resp.FeeCurrency)
+	}
+	if resp.Organizer != "" {
+		t.Errorf("Organizer = %q, want it dropped", resp.Organizer)
+	}
+}
@@ -0,0 +1,434 @@
+package extract
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"hackflow-api/internal/models"
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/telegram"
+)
+
+// Fields scored by Evaluate, in report order.
+var Fields = []string{"title", "date", "deadline", "city", "format", "link", "status"}
+
+// Sample is a labeled post from the extraction corpus.
+type Sample struct {
+	ID      string `json:"id"`
+	Channel string `json:"channel"`
+	// Today is the date the post is evaluated on (YYYY-MM-DD), so statuses
+	// and years stay stable as the corpus ages.
+	Today       string       `json:"today"`
+	PublishedAt string       `json:"publishedAt"`
+	Text        string       `json:"text"`
+	Links       []SampleLink `json:"links"`
+	Expected    Response     `json:"expected"`
+}
+
+// SampleLink is a link of a corpus post.
+type SampleLink struct {
+	URL  string `json:"url"`
+	Text string `json:"text"`
+}
+
+// Post converts the sample into the scraper's representation.
+func (s Sample) Post() (telegram.Post, time.Time, error) {
+	today, err := time.Parse("2006-01-02", s.Today)
+	if err != nil {
+		return telegram.Post{}, time.Time{}, fmt.Errorf("sample %s: invalid today: %w", s.ID, err)
+	}
+	published, err := time.Parse("2006-01-02", s.PublishedAt)
+	if err != nil {
+		return telegram.Post{}, time.Time{}, fmt.Errorf("sample %s: invalid publishedAt: %w", s.ID, err)
+	}
+
+	post := telegram.Post{Channel: s.Channel, Text: s.Text, PublishedAt: published}
+	for _, l := range s.Links {
+		post.Links = append(post.Links, telegram.Link{URL: l.URL, Text: l.Text, Kind: telegram.LinkText})
+	}
+	return post, today, nil
+}
+
+// LoadCorpus reads a JSON Lines file of samples. Blank lines and lines
+// starting with "#" are ignored.
+func LoadCorpus(path string) ([]Sample, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var samples []Sample
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" || strings.HasPrefix(raw, "#") {
+			continue
+		}
+		var s Sample
+		if err := json.Unmarshal([]byte(raw), &s); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		samples = append(samples, s)
+	}
+	return samples, scanner.Err()
+}
+
+// Outcome is the extraction of one sample and which fields matched.
+type Outcome struct {
+	Sample Sample
+	Result Result
+	Err    error
+	// Correct maps every scored field to whether it matched the label.
+	Correct map[string]bool
+}
+
+// Diff is a field that does not match its label.
+type Diff struct {
+	SampleID string
+	Field    string
+	Expected string
+	Got      string
+}
+
+// Report summarizes extraction quality over a corpus.
+type Report struct {
+	Outcomes []Outcome
+	Diffs    []Diff
+	// PromptVersion is the version reported by the extractor, if any.
+	PromptVersion string
+}
+
+// Accuracy returns the share of samples whose field matched the label.
+// Samples that failed to extract count as misses for every field.
+func (r Report) Accuracy(field string) float64 {
+	if len(r.Outcomes) == 0 {
+		return 0
+	}
+	correct := 0
+	for _, o := range r.Outcomes {
+		if o.Correct[field] {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(r.Outcomes))
+}
+
+// Errors returns the number of samples the extractor failed on.
+func (r Report) Errors() int {
+	n := 0
+	for _, o := range r.Outcomes {
+		if o.Err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Evaluate runs the extractor over every sample and compares the result with
+// the expected fields. Extraction errors are recorded, not returned; only a
+// cancelled context or a malformed sample stops the run.
+func Evaluate(ctx context.Context, ex Extractor, samples []Sample, loc region.Locale) (Report, error) {
+	var r Report
+	for _, s := range samples {
+		post, today, err := s.Post()
+		if err != nil {
+			return r, err
+		}
+
+		res, err := ex.Extract(ctx, post, today)
+		if ctx.Err() != nil {
+			return r, ctx.Err()
+		}
+		r.add(s, res, err, loc)
+	}
+	return r, nil
+}
+
+// EvaluateBatch is Evaluate for a batch extractor: samples are sent in
+// batches of up to size posts. A batch prompt has a single "today", so only
+// samples with the same Today share a batch. A failed batch counts as an
+// error for each of its samples.
+func EvaluateBatch(ctx context.Context, ex BatchExtractor, samples []Sample, loc region.Locale, size int) (Report, error) {
+	size = max(size, 1)
+
+	var days []string
+	byDay := make(map[string][]Sample)
+	for _, s := range samples {
+		if _, ok := byDay[s.Today]; !ok {
+			days = append(days, s.Today)
+		}
+		byDay[s.Today] = append(byDay[s.Today], s)
+	}
+
+	var r Report
+	for _, day := range days {
+		group := byDay[day]
+		for start := 0; start < len(group); start += size {
+			batch := group[start:min(start+size, len(group))]
+
+			posts := make([]telegram.Post, len(batch))
+			var today time.Time
+			for i, s := range batch {
+				post, t, err := s.Post()
+				if err != nil {
+					return r, err
+				}
+				posts[i], today = post, t
+			}
+
+			results, errs, err := ex.ExtractBatch(ctx, posts, today)
+			if ctx.Err() != nil {
+				return r, ctx.Err()
+			}
+			for i, s := range batch {
+				if err != nil {
+					r.add(s, Result{}, err, loc)
+					continue
+				}
+				r.add(s, results[i], errs[i], loc)
+			}
+		}
+	}
+	return r, nil
+}
+
+// add scores the extraction of one sample.
+func (r *Report) add(s Sample, res Result, err error, loc region.Locale) {
+	if res.PromptVersion != "" {
+		r.PromptVersion = res.PromptVersion
+	}
+
+	o := Outcome{Sample: s, Result: res, Err: err, Correct: make(map[string]bool, len(Fields))}
+	for _, field := range Fields {
+		want, got := fieldValue(s.Expected, field), fieldValue(res.Response, field)
+		if err == nil && sameField(field, want, got, loc) {
+			o.Correct[field] = true
+			continue
+		}
+		if err != nil {
+			got = "error: " + err.Error()
+		}
+		r.Diffs = append(r.Diffs, Diff{SampleID: s.ID, Field: field, Expected: want, Got: got})
+	}
+	r.Outcomes = append(r.Outcomes, o)
+}
+
+func fieldValue(resp Response, field string) string {
+	switch field {
+	case "title":
+		return resp.Title
+	case "date":
+		return resp.DateStr
+	case "deadline":
+		return resp.Deadline
+	case "city":
+		if resp.City != nil {
+			return *resp.City
+		}
+	case "format":
+		return resp.Format
+	case "link":
+		if resp.Link != nil {
+			return *resp.Link
+		}
+	case "status":
+		return resp.Status
+	}
+	return ""
+}
+
+// sameField compares values the way a reviewer would: titles and dates ignore
+// case and punctuation, cities are normalized for the region, links ignore
+// the scheme, "www." and a trailing slash.
+func sameField(field, want, got string, loc region.Locale) bool {
+	switch field {
+	case "title", "date":
+		return models.NormalizeTitle(want) == models.NormalizeTitle(got)
+	case "city":
+		return models.NormalizeTitle(loc.Region.NormalizeCity(want, loc.Lang)) ==
+			models.NormalizeTitle(loc.Region.NormalizeCity(got, loc.Lang))
+	case "format":
+		return models.ParseFormat(want) == models.ParseFormat(got)
+	case "status":
+		return strings.EqualFold(strings.TrimSpace(want), strings.TrimSpace(got))
+	case "link":
+		return normalizeLink(want) == normalizeLink(got)
+	default:
+		return strings.TrimSpace(want) == strings.TrimSpace(got)
+	}
+}
+
+func normalizeLink(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return strings.TrimSuffix(raw, "/")
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	return host + strings.TrimSuffix(u.Path, "/") + u.RawQuery
+}
+
+// Baseline is a stored evaluation run that later runs are compared with.
+type Baseline struct {
+	PromptVersion string             `json:"promptVersion"`
+	Model         string             `json:"model,omitempty"`
+	Accuracy      map[string]float64 `json:"accuracy"`
+	// Correct lists, per sample, the fields that matched their label.
+	Correct map[string][]string `json:"correct"`
+}
+
+// Baseline captures the report for later comparison.
+func (r Report) Baseline(model string) Baseline {
+	b := Baseline{
+		PromptVersion: r.PromptVersion,
+		Model:         model,
+		Accuracy:      make(map[string]float64, len(Fields)),
+		Correct:       make(map[string][]string, len(r.Outcomes)),
+	}
+	for _, field := range Fields {
+		b.Accuracy[field] = r.Accuracy(field)
+	}
+	for _, o := range r.Outcomes {
+		fields := []string{}
+		for _, field := range Fields {
+			if o.Correct[field] {
+				fields = append(fields, field)
+			}
+		}
+		b.Correct[o.Sample.ID] = fields
+	}
+	return b
+}
+
+// Regressions lists the sample fields that matched in the baseline but no
+// longer do. Samples missing from the baseline are not compared.
+func (r Report) Regressions(b *Baseline) []Diff {
+	diffs := make(map[string]Diff, len(r.Diffs))
+	for _, d := range r.Diffs {
+		diffs[d.SampleID+"/"+d.Field] = d
+	}
+
+	var out []Diff
+	for _, o := range r.Outcomes {
+		for _, field := range b.Correct[o.Sample.ID] {
+			if !o.Correct[field] {
+				out = append(out, diffs[o.Sample.ID+"/"+field])
+			}
+		}
+	}
+	return out
+}
+
+// LoadBaseline reads a baseline file. A missing file yields an error
+// matching os.ErrNotExist.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return &b, nil
+}
+
+// SaveBaseline writes b as indented JSON.
+func SaveBaseline(path string, b Baseline) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Prediction is a stored extraction of a corpus sample, so a run against the
+// live model can be replayed offline.
+type Prediction struct {
+	ID     string `json:"id"`
+	Result Result `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SavePredictions writes the outcomes of a report as JSON Lines.
+func SavePredictions(path string, r Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetEscapeHTML(false)
+	for _, o := range r.Outcomes {
+		p := Prediction{ID: o.Sample.ID, Result: o.Result}
+		if o.Err != nil {
+			p.Error = o.Err.Error()
+		}
+		if err := enc.Encode(p); err != nil {
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// Replay is an Extractor answering with stored predictions instead of
+// calling a model. Posts are matched by text.
+type Replay struct {
+	byText map[string]Prediction
+}
+
+// NewReplay loads predictions saved by SavePredictions for the given corpus.
+func NewReplay(path string, samples []Sample) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	byID := make(map[string]Prediction)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+		var p Prediction
+		if err := json.Unmarshal([]byte(raw), &p); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		byID[p.ID] = p
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	r := &Replay{byText: make(map[string]Prediction, len(samples))}
+	for _, s := range samples {
+		if p, ok := byID[s.ID]; ok {
+			r.byText[s.Text] = p
+		}
+	}
+	return r, nil
+}
+
+func (r *Replay) Extract(ctx context.Context, post telegram.Post, today time.Time) (Result, error) {
+	p, ok := r.byText[post.Text]
+	if !ok {
+		return Result{}, errors.New("no stored prediction for post")
+	}
+	if p.Error != "" {
+		return p.Result, errors.New(p.Error)
+	}
+	return p.Result, nil
+}
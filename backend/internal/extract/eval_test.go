@@ -0,0 +1,95 @@
+package extract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/telegram"
+)
+
+// TestStoredPredictions replays the predictions stored next to the corpus
+// and fails on any field that regressed against the baseline, so scoring
+// changes are checked without calling the model.
+func TestStoredPredictions(t *testing.T) {
+	loc, samples, replay := loadStored(t)
+	baseline, err := LoadBaseline("testdata/baseline.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Evaluate(context.Background(), replay, samples, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := report.Errors(); n > 0 {
+		t.Errorf("%d sample(s) failed to extract", n)
+	}
+	for _, d := range report.Regressions(baseline) {
+		t.Errorf("%s: %s regressed: want %q, got %q", d.SampleID, d.Field, d.Expected, d.Got)
+	}
+}
+
+// batchReplay answers batches from stored predictions and records the
+// batches it was called with.
+type batchReplay struct {
+	*Replay
+	calls []int
+	days  []time.Time
+}
+
+func (b *batchReplay) ExtractBatch(ctx context.Context, posts []telegram.Post, today time.Time) ([]Result, []error, error) {
+	b.calls = append(b.calls, len(posts))
+	b.days = append(b.days, today)
+	results := make([]Result, len(posts))
+	errs := make([]error, len(posts))
+	for i, post := range posts {
+		results[i], errs[i] = b.Extract(ctx, post, today)
+	}
+	return results, errs, nil
+}
+
+func TestEvaluateBatch(t *testing.T) {
+	loc, samples, replay := loadStored(t)
+	// Three samples share a day; the rest fall into batches of their own.
+	for i := range samples[:3] {
+		samples[i].Today = "2025-03-01"
+	}
+
+	bx := &batchReplay{Replay: replay}
+	report, err := EvaluateBatch(context.Background(), bx, samples, loc, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Outcomes) != len(samples) {
+		t.Fatalf("got %d outcomes, want %d", len(report.Outcomes), len(samples))
+	}
+	if bx.calls[0] != 2 || bx.calls[1] != 1 || !bx.days[0].Equal(bx.days[1]) {
+		t.Errorf("first batches = %v on %v, want [2 1] on the same day", bx.calls[:2], bx.days[:2])
+	}
+	for _, d := range report.Diffs {
+		t.Errorf("%s: %s = %q, want %q", d.SampleID, d.Field, d.Got, d.Expected)
+	}
+}
+
+func loadStored(t *testing.T) (region.Locale, []Sample, *Replay) {
+	t.Helper()
+	regions, err := region.Load("", "kz", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, err := regions.Resolve("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples, err := LoadCorpus("testdata/corpus.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay, err := NewReplay("testdata/predictions.jsonl", samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc, samples, replay
+}
@@ -0,0 +1,304 @@
+// Package extract turns Telegram posts into structured hackathon fields with
+// an LLM. It is shared by the scraper and the offline evaluation harness.
+package extract
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"hackflow-api/internal/llmusage"
+	"hackflow-api/internal/models"
+	"hackflow-api/internal/prompts"
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/taxonomy"
+	"hackflow-api/internal/telegram"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// DefaultModel is the Gemini model used for post extraction.
+const DefaultModel = "gemini-2.5-flash-lite"
+
+// ErrNoHackathon is returned when the model found no event in the post.
+var ErrNoHackathon = errors.New("no hackathon in post")
+
+// Response is the strict JSON the model returns for a post.
+type Response struct {
+	Title string `json:"title"`
+	// SourceTitle is the title verbatim as written in the post; Title may be
+	// translated into the output language.
+	SourceTitle string  `json:"source_title,omitempty"`
+	DateStr     string  `json:"date_str"`
+	Deadline    string  `json:"deadline"`
+	Format      string  `json:"format"`
+	City        *string `json:"city"`
+	AgeLimit    string  `json:"ageLimit"`
+	Link        *string `json:"link"`
+	Status      string  `json:"status"`
+	// Cities lists every host city; National marks nationwide events.
+	Cities   []string `json:"cities,omitempty"`
+	National bool     `json:"national,omitempty"`
+	// Prizes, tracks, team size, fee and language; Organizer is a name.
+	models.EventDetails
+	Organizer string `json:"organizer,omitempty"`
+	// Tags are the taxonomy slugs the model picked.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Result is an extraction together with the prompt version that produced it.
+type Result struct {
+	Response
+	PromptVersion string `json:"promptVersion"`
+}
+
+// Extractor extracts hackathon fields from a post. today is the date the
+// LIVE/DEAD status is computed against.
+type Extractor interface {
+	Extract(ctx context.Context, post telegram.Post, today time.Time) (Result, error)
+}
+
+// BatchExtractor extracts several posts with one model call. results and
+// errs are indexed like posts; errs[i] is the per-post error (ErrNoHackathon,
+// ErrUngrounded, or a post the model skipped), while err fails the whole
+// batch.
+type BatchExtractor interface {
+	ExtractBatch(ctx context.Context, posts []telegram.Post, today time.Time) (results []Result, errs []error, err error)
+}
+
+// ErrMissing is returned for a post of a batch the model gave no answer for.
+var ErrMissing = errors.New("no answer for post in batch")
+
+// Gemini extracts fields with the Gemini API using the post prompt templates.
+// The client is shared by all calls and must outlive the extractor.
+type Gemini struct {
+	Client *genai.Client
+	Model  string
+	Locale region.Locale
+	// Usage records tokens under llmusage.CallerScraper and enforces its
+	// daily budget; nil disables accounting
+	Usage *llmusage.Tracker
+	// Tags is the taxonomy offered to the model; nil leaves tags out
+	Tags *taxonomy.Taxonomy
+}
+
+// NewGemini creates a Gemini extractor writing fields in the locale's language.
+func NewGemini(client *genai.Client, loc region.Locale) *Gemini {
+	return &Gemini{Client: client, Model: DefaultModel, Locale: loc}
+}
+
+func (g *Gemini) Extract(ctx context.Context, post telegram.Post, today time.Time) (Result, error) {
+	// The post is untrusted: drop lines that read like instructions to the model
+	post = sanitizePost(post)
+
+	prompt, err := prompts.Post(prompts.PostData{
+		Date:        today.Format("2006-01-02"),
+		PublishedAt: post.PublishedAt.Format("2006-01-02"),
+		Text:        post.Text,
+		Links:       post.Links,
+		Locale:      g.Locale,
+		Tags:        g.Tags.Slugs(),
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	raw, err := g.generate(ctx, prompt)
+	if err != nil {
+		return Result{}, err
+	}
+
+	parsed, err := ParseResponse(raw)
+	if err == nil {
+		err = Check(&parsed, post, today, g.Locale)
+	}
+	return Result{Response: parsed, PromptVersion: prompt.Version}, err
+}
+
+// ExtractBatch extracts all posts with a single call using the batch prompt.
+func (g *Gemini) ExtractBatch(ctx context.Context, posts []telegram.Post, today time.Time) ([]Result, []error, error) {
+	sanitized := make([]telegram.Post, len(posts))
+	batch := make([]prompts.BatchPost, len(posts))
+	for i, post := range posts {
+		sanitized[i] = sanitizePost(post)
+		batch[i] = prompts.BatchPost{
+			PublishedAt: post.PublishedAt.Format("2006-01-02"),
+			Text:        sanitized[i].Text,
+			Links:       sanitized[i].Links,
+		}
+	}
+
+	prompt, err := prompts.Posts(prompts.PostsData{
+		Date:   today.Format("2006-01-02"),
+		Posts:  batch,
+		Locale: g.Locale,
+		Tags:   g.Tags.Slugs(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, err := g.generate(ctx, prompt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parsed, err := parseBatch(raw, len(posts))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := make([]Result, len(posts))
+	errs := make([]error, len(posts))
+	for i, resp := range parsed {
+		if resp == nil {
+			errs[i] = ErrMissing
+			continue
+		}
+		err := normalize(resp)
+		if err == nil {
+			err = Check(resp, sanitized[i], today, g.Locale)
+		}
+		results[i] = Result{Response: *resp, PromptVersion: prompt.Version}
+		errs[i] = err
+	}
+	return results, errs, nil
+}
+
+// generate runs the prompt and returns the raw text of the first candidate.
+func (g *Gemini) generate(ctx context.Context, prompt prompts.Prompt) (string, error) {
+	if err := g.Usage.Allow(ctx, llmusage.CallerScraper); err != nil {
+		return "", err
+	}
+
+	model := g.Client.GenerativeModel(g.Model)
+	model.SetTemperature(0.1)
+	model.ResponseMIMEType = "application/json"
+
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt.Text))
+	g.Usage.Record(ctx, llmusage.CallerScraper, g.Model, llmusage.FromResponse(resp))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate content: %w", err)
+	}
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", errors.New("empty response from Gemini")
+	}
+	return fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0]), nil
+}
+
+// ParseResponse decodes the model output, tolerating Markdown code fences and
+// "null" strings in place of JSON nulls.
+func ParseResponse(raw string) (Response, error) {
+	var resp Response
+	if err := json.Unmarshal([]byte(stripFences(raw)), &resp); err != nil {
+		return Response{}, fmt.Errorf("failed to parse model JSON: %w", err)
+	}
+	return resp, normalize(&resp)
+}
+
+// parseBatch decodes the output of the batch prompt for n posts. The
+// result is indexed by post; posts the model skipped are nil. Answers are
+// matched by their "post" number, falling back to array order when the model
+// omitted the numbers. Answers are not normalized yet.
+func parseBatch(raw string, n int) ([]*Response, error) {
+	var items []struct {
+		Post int `json:"post"`
+		Response
+	}
+	if err := json.Unmarshal([]byte(stripFences(raw)), &items); err != nil {
+		return nil, fmt.Errorf("failed to parse model JSON: %w", err)
+	}
+
+	out := make([]*Response, n)
+	for i, item := range items {
+		idx := item.Post - 1
+		if item.Post == 0 {
+			idx = i
+		}
+		if idx < 0 || idx >= n || out[idx] != nil {
+			slog.Warn("Ignoring batch answer with unknown post number", "post", item.Post, "posts", n)
+			continue
+		}
+		resp := item.Response
+		out[idx] = &resp
+	}
+	return out, nil
+}
+
+func stripFences(raw string) string {
+	jsonText := strings.TrimSpace(raw)
+	jsonText = strings.TrimPrefix(jsonText, "```json")
+	jsonText = strings.TrimPrefix(jsonText, "```")
+	jsonText = strings.TrimSuffix(jsonText, "```")
+	return strings.TrimSpace(jsonText)
+}
+
+// normalize replaces "null" strings with real nulls and reports
+// ErrNoHackathon for an empty title.
+func normalize(resp *Response) error {
+	if resp.City != nil && *resp.City == "null" {
+		resp.City = nil
+	}
+	if resp.Link != nil && *resp.Link == "null" {
+		resp.Link = nil
+	}
+	if resp.Deadline == "null" {
+		resp.Deadline = ""
+	}
+	if resp.Title == "" || resp.Title == "null" {
+		return ErrNoHackathon
+	}
+	return nil
+}
+
+// Hackathon converts the result into a model, normalizing the city name for
+// the locale. The registration link is left to the caller's link rules, the
+// host cities to CitySlugs and the organizer to database.LinkOrganizer.
+func (r Result) Hackathon(loc region.Locale) *models.Hackathon {
+	h := &models.Hackathon{
+		Title:         r.Title,
+		Date:          r.DateStr,
+		Format:        models.ParseFormat(r.Format),
+		Status:        r.Status,
+		PromptVersion: r.PromptVersion,
+	}
+
+	if r.City != nil {
+		h.City, _ = loc.City(*r.City)
+	}
+	h.National = r.National
+	h.MinAge, h.MaxAge, h.Audiences = models.ParseAgeLimit(r.AgeLimit)
+	h.EventDetails = r.EventDetails
+	h.EventDetails.Normalize()
+	if r.Link != nil {
+		h.Link = *r.Link
+	}
+	if r.Deadline != "" {
+		if d, err := time.Parse("2006-01-02", r.Deadline); err == nil {
+			h.Deadline = &d
+		} else {
+			slog.Warn("Failed to parse deadline from model", "deadline", r.Deadline, "error", err)
+		}
+	}
+	return h
+}
+
+// TagSlugs returns the taxonomy slugs picked by the model together with the
+// tags whose keywords occur in the title or tracks.
+func (r Result) TagSlugs(tax *taxonomy.Taxonomy) []string {
+	return tax.Assign(r.Tags, append([]string{r.Title}, r.Tracks...)...)
+}
+
+// CitySlugs returns the slugs of the known host cities for the locale,
+// including the national cities of nationwide events.
+func (r Result) CitySlugs(loc region.Locale) []string {
+	names := r.Cities
+	if r.City != nil {
+		names = append([]string{*r.City}, names...)
+	}
+	return loc.CitySlugs(names, r.National)
+}
@@ -0,0 +1,288 @@
+package guard
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"hackflow-api/internal/models"
+)
+
+var numberRe = regexp.MustCompile(`\d+`)
+
+// TitleGrounded checks that at least half of the distinctive title tokens
+// (4+ characters or containing digits) occur in text. Tokens are compared by
+// a 5-rune prefix to tolerate inflection ("хакатона" vs "хакатон"). A
+// translated title cannot be verified against the source, so callers check
+// the verbatim title the model copied from it.
+func TitleGrounded(title, text string) bool {
+	haystack := " " + models.NormalizeTitle(text) + " "
+
+	total, matched := 0, 0
+	for _, token := range strings.Fields(models.NormalizeTitle(title)) {
+		runes := []rune(token)
+		if len(runes) < 4 && !numberRe.MatchString(token) {
+			continue
+		}
+		total++
+
+		needle := token
+		if len(runes) > 5 {
+			needle = string(runes[:5])
+		}
+		if strings.Contains(haystack, " "+needle) {
+			matched++
+		}
+	}
+
+	return total > 0 && matched*2 >= total
+}
+
+// GroundTitle returns the title to store for an event whose verbatim
+// sourceTitle was checked with TitleGrounded. A title that does not occur in
+// text is only kept as a translation into lang: it must be written in the
+// script of lang, not in that of sourceTitle, and carry no numbers missing
+// from text. Otherwise the model rewrote the title, and sourceTitle is
+// returned instead. Translations between languages sharing a script (ru and
+// kk) fall back to sourceTitle too.
+func GroundTitle(title, sourceTitle, text, lang string) string {
+	if strings.TrimSpace(sourceTitle) == "" || TitleGrounded(title, text) {
+		return title
+	}
+	translated := script(title)
+	if translated != script(sourceTitle) && DateGrounded(title, text) &&
+		(langScripts[lang] == nil || langScripts[lang] == translated) {
+		return title
+	}
+	return sourceTitle
+}
+
+// langScripts maps output languages to the script they are written in.
+var langScripts = map[string]*unicode.RangeTable{
+	"ru": unicode.Cyrillic,
+	"kk": unicode.Cyrillic,
+	"en": unicode.Latin,
+	"uz": unicode.Latin,
+}
+
+// script returns the script most letters of s are written in: Cyrillic,
+// Latin or nil if there are no letters of either.
+func script(s string) *unicode.RangeTable {
+	cyrillic, latin := 0, 0
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	switch {
+	case cyrillic == 0 && latin == 0:
+		return nil
+	case cyrillic >= latin:
+		return unicode.Cyrillic
+	default:
+		return unicode.Latin
+	}
+}
+
+// DateGrounded checks that every number in the date string (days, years)
+// occurs in text. Dates without numbers ("Даты уточняются") make no claim and
+// pass.
+func DateGrounded(date, text string) bool {
+	numbers := numberRe.FindAllString(date, -1)
+	if len(numbers) == 0 {
+		return true
+	}
+
+	present := make(map[string]bool)
+	for _, n := range numberRe.FindAllString(text, -1) {
+		present[strings.TrimLeft(n, "0")] = true
+	}
+	for _, n := range numbers {
+		if !present[strings.TrimLeft(n, "0")] {
+			return false
+		}
+	}
+	return true
+}
+
+// DeadlineGrounded checks that the day of a YYYY-MM-DD deadline occurs in
+// text. The year and month are often implied ("до 15-го"), so only the day
+// is required. Empty or malformed deadlines pass; they are validated
+// elsewhere.
+func DeadlineGrounded(deadline, text string) bool {
+	d, err := time.Parse("2006-01-02", deadline)
+	if err != nil {
+		return true
+	}
+	return DateGrounded(d.Format("2"), text)
+}
+
+// LinkGrounded checks that link points to one of the given URLs or to a URL
+// written in text. URLs are compared without scheme, "www." and trailing
+// slash.
+func LinkGrounded(link, text string, urls []string) bool {
+	want := normalizeURL(link)
+	if want == "" {
+		return false
+	}
+	for _, u := range append(urls, urlRe.FindAllString(text, -1)...) {
+		if normalizeURL(u) == want {
+			return true
+		}
+	}
+	return false
+}
+
+var urlRe = regexp.MustCompile(`(?i)(https?://)?([\w-]+\.)+[a-z]{2,}(/[^\s)"'»<>]*)?`)
+
+func normalizeURL(raw string) string {
+	raw = strings.TrimRight(strings.TrimSpace(raw), ".,;:!?")
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	out := host + strings.TrimSuffix(u.Path, "/")
+	if u.RawQuery != "" {
+		out += "?" + u.RawQuery
+	}
+	return out
+}
+
+// amountRe matches a number with optional thousands separators ("5 000 000",
+// "5,000,000") or a decimal part ("1,5"), followed by an optional multiplier
+// ("тыс", "k", "млн", "M").
+var amountRe = regexp.MustCompile(`(?i)(\d{1,3}(?:[ \x{00a0}\x{202f}.,]\d{3})+|\d+(?:[.,]\d{1,2})?)(?:\s*(тыс|млн|миллион|млрд|million|mln|bn|k|к|m|м)(?:\P{L}|$))?`)
+
+var multipliers = map[string]float64{
+	"тыс": 1e3, "k": 1e3, "к": 1e3,
+	"млн": 1e6, "миллион": 1e6, "million": 1e6, "mln": 1e6, "m": 1e6, "м": 1e6,
+	"млрд": 1e9, "bn": 1e9,
+}
+
+// AmountGrounded checks that amount is written in text, with or without
+// thousands separators or as a short form such as "5 млн" or "1,5M".
+func AmountGrounded(amount int64, text string) bool {
+	for _, m := range amountRe.FindAllStringSubmatch(text, -1) {
+		digits := m[1]
+		var value float64
+		if sep := strings.LastIndexAny(digits, ".,"); sep >= 0 && len(digits)-sep <= 3 {
+			// "1,5" or "2.25": a decimal part, not a thousands group
+			whole, _ := strconv.ParseFloat(strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "", ".", "", ",", "").Replace(digits[:sep]), 64)
+			frac, _ := strconv.ParseFloat("0."+digits[sep+1:], 64)
+			value = whole + frac
+		} else {
+			value, _ = strconv.ParseFloat(strings.Map(func(r rune) rune {
+				if r >= '0' && r <= '9' {
+					return r
+				}
+				return -1
+			}, digits), 64)
+		}
+		if int64(value) == amount {
+			return true
+		}
+		if mult, ok := multipliers[strings.ToLower(m[2])]; ok && int64(value*mult+0.5) == amount {
+			return true
+		}
+	}
+	return false
+}
+
+var freeRe = regexp.MustCompile(`(?i)(бесплатн|free|тегін|без взнос|no fee)`)
+
+// FreeGrounded checks that text says participation is free.
+func FreeGrounded(text string) bool {
+	return freeRe.MatchString(text)
+}
+
+var soloRe = regexp.MustCompile(`(?i)(соло|solo|индивидуальн|individual|жеке)`)
+
+// CountGrounded checks that a small count such as a team size occurs in text
+// as a number; a count of one may also be written as "соло".
+func CountGrounded(n int, text string) bool {
+	if n == 1 && soloRe.MatchString(text) {
+		return true
+	}
+	want := strconv.Itoa(n)
+	for _, m := range numberRe.FindAllString(text, -1) {
+		if strings.TrimLeft(m, "0") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// NameGrounded checks that a name such as an organizer occurs in text: as a
+// whole phrase, which also covers short names like "NU", or by its
+// distinctive tokens as in TitleGrounded.
+func NameGrounded(name, text string) bool {
+	norm := models.NormalizeTitle(name)
+	if norm == "" {
+		return false
+	}
+	if strings.Contains(" "+models.NormalizeTitle(text)+" ", " "+norm+" ") {
+		return true
+	}
+	return TitleGrounded(name, text)
+}
+
+// GroundDetails clears the prize pool, fee, team sizes and organizer that do
+// not occur in text, together with their currencies, and returns the names of
+// the cleared fields.
+func GroundDetails(d *models.EventDetails, organizer *string, text string) []string {
+	var dropped []string
+	if d.PrizePool != nil && !AmountGrounded(*d.PrizePool, text) {
+		d.PrizePool, d.PrizeCurrency = nil, ""
+		dropped = append(dropped, "prizePool")
+	}
+	if d.Fee != nil {
+		grounded := AmountGrounded(*d.Fee, text)
+		if *d.Fee == 0 {
+			grounded = FreeGrounded(text)
+		}
+		if !grounded {
+			d.Fee, d.FeeCurrency = nil, ""
+			dropped = append(dropped, "fee")
+		}
+	}
+	if d.TeamSizeMin != nil && !CountGrounded(*d.TeamSizeMin, text) {
+		d.TeamSizeMin = nil
+		dropped = append(dropped, "teamSizeMin")
+	}
+	if d.TeamSizeMax != nil && !CountGrounded(*d.TeamSizeMax, text) {
+		d.TeamSizeMax = nil
+		dropped = append(dropped, "teamSizeMax")
+	}
+	if organizer != nil && strings.TrimSpace(*organizer) != "" && !NameGrounded(*organizer, text) {
+		*organizer = ""
+		dropped = append(dropped, "organizer")
+	}
+	return dropped
+}
+
+// StatusForDeadline downgrades a LIVE status to DEAD when the deadline has
+// already passed, whatever the model (or the source text) claimed.
+func StatusForDeadline(status, deadline string, today time.Time) string {
+	d, err := time.Parse("2006-01-02", deadline)
+	if err != nil || status != "LIVE" {
+		return status
+	}
+	y, m, day := today.Date()
+	if d.Before(time.Date(y, m, day, 0, 0, 0, 0, time.UTC)) {
+		return "DEAD"
+	}
+	return status
+}
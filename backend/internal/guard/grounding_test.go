@@ -0,0 +1,207 @@
+package guard
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"hackflow-api/internal/models"
+)
+
+func TestTitleGrounded(t *testing.T) {
+	const post = "🚀 Astana Hub объявляет хакатон AI Challenge 2025! Финал хакатона пройдёт в Астане."
+	tests := []struct {
+		title string
+		want  bool
+	}{
+		{"AI Challenge 2025", true},
+		{"Astana Hub AI Challenge", true},
+		{"ASTANA HUB — AI CHALLENGE", true},
+		{"Хакатоны Astana Hub", true},   // inflection: 5-rune prefix
+		{"Free iPhone Giveaway", false}, // planted title
+		{"AI Challenge 2026", true},     // half of the tokens is enough
+		{"Digital Almaty 2026", false},  // no token occurs
+		{"AI", false},                   // nothing distinctive to check
+		{"Челлендж по ИИ", false},       // translation cannot be verified
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := TitleGrounded(tt.title, post); got != tt.want {
+			t.Errorf("TitleGrounded(%q) = %v, want %v", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestGroundTitle(t *testing.T) {
+	const post = "Astana Hub объявляет хакатон AI Challenge 2025! Финал пройдёт в Астане."
+	tests := []struct {
+		title, source, lang string
+		want                string
+	}{
+		{"AI Challenge 2025", "AI Challenge 2025", "ru", "AI Challenge 2025"},
+		{"Хакатон AI Challenge", "хакатон AI Challenge", "en", "Хакатон AI Challenge"}, // grounded itself
+		{"Челлендж ИИ 2025", "AI Challenge 2025", "ru", "Челлендж ИИ 2025"},            // translation
+		{"Челлендж ИИ 2025", "AI Challenge 2025", "", "Челлендж ИИ 2025"},              // unknown language
+		{"Челлендж по ИИ", "AI Challenge 2025", "en", "AI Challenge 2025"},             // not in the output language
+		{"Free iPhone Giveaway", "AI Challenge 2025", "en", "AI Challenge 2025"},       // same script, rewritten
+		{"Бесплатный айфон 2099", "AI Challenge 2025", "ru", "AI Challenge 2025"},      // number not in post
+		{"Free iPhone Giveaway", "хакатон Astana", "ru", "хакатон Astana"},             // output language matches post
+		{"Astana Hackathon", "хакатон Astana", "en", "Astana Hackathon"},               // translation
+		{"Free iPhone Giveaway", "", "ru", "Free iPhone Giveaway"},                     // checked by TitleGrounded
+	}
+	for _, tt := range tests {
+		if got := GroundTitle(tt.title, tt.source, post, tt.lang); got != tt.want {
+			t.Errorf("GroundTitle(%q, %q, %q) = %q, want %q", tt.title, tt.source, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestDateGrounded(t *testing.T) {
+	const post = "📅 22–23 марта, регистрация до 05.03"
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{"22-23 марта", true},
+		{"5 марта", true}, // leading zeros are ignored
+		{"22-24 марта", false},
+		{"Даты уточняются", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		if got := DateGrounded(tt.date, post); got != tt.want {
+			t.Errorf("DateGrounded(%q) = %v, want %v", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestDeadlineGrounded(t *testing.T) {
+	const post = "Регистрация до 15 марта"
+	tests := []struct {
+		deadline string
+		want     bool
+	}{
+		{"2025-03-15", true},
+		{"2026-04-15", true}, // only the day is required
+		{"2025-03-16", false},
+		{"", true},
+		{"15 марта", true}, // malformed, validated elsewhere
+	}
+	for _, tt := range tests {
+		if got := DeadlineGrounded(tt.deadline, post); got != tt.want {
+			t.Errorf("DeadlineGrounded(%q) = %v, want %v", tt.deadline, got, tt.want)
+		}
+	}
+}
+
+func TestLinkGrounded(t *testing.T) {
+	const post = "Регистрация: https://www.astanahub.com/ai-challenge/, подробнее на decentrathon.kz."
+	urls := []string{"https://forms.gle/abc123"}
+	tests := []struct {
+		link string
+		want bool
+	}{
+		{"https://astanahub.com/ai-challenge", true},
+		{"http://www.astanahub.com/ai-challenge/", true},
+		{"decentrathon.kz", true},
+		{"https://forms.gle/abc123", true},
+		{"https://forms.gle/abc124", false},
+		{"https://evil.example/register", false},
+		{"https://astanahub.com/ai-challenge?ref=x", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := LinkGrounded(tt.link, post, urls); got != tt.want {
+			t.Errorf("LinkGrounded(%q) = %v, want %v", tt.link, got, tt.want)
+		}
+	}
+}
+
+func TestStatusForDeadline(t *testing.T) {
+	today := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		status, deadline, want string
+	}{
+		{"LIVE", "2025-03-09", "DEAD"},
+		{"LIVE", "2025-03-10", "LIVE"}, // the deadline day itself is still open
+		{"LIVE", "2025-04-01", "LIVE"},
+		{"DEAD", "2025-04-01", "DEAD"},
+		{"LIVE", "", "LIVE"},
+	}
+	for _, tt := range tests {
+		if got := StatusForDeadline(tt.status, tt.deadline, today); got != tt.want {
+			t.Errorf("StatusForDeadline(%q, %q) = %q, want %q", tt.status, tt.deadline, got, tt.want)
+		}
+	}
+}
+
+func TestAmountGrounded(t *testing.T) {
+	const post = "Призовой фонд 5 000 000 ₸, для финалистов 1,5 млн. Взнос 2000тг, мест: 5"
+	tests := []struct {
+		amount int64
+		want   bool
+	}{
+		{5000000, true},
+		{1500000, true}, // short form with a multiplier
+		{2000, true},    // currency glued to the number
+		{5, true},
+		{50000000, false},
+		{10000, false},
+		{5000, false}, // "мест" is not a multiplier
+	}
+	for _, tt := range tests {
+		if got := AmountGrounded(tt.amount, post); got != tt.want {
+			t.Errorf("AmountGrounded(%d) = %v, want %v", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestGroundDetails(t *testing.T) {
+	const post = "Хакатон от Astana Hub. Призовой фонд $10,000, участие бесплатное, команды 2–4 человека."
+	ptr := func(n int64) *int64 { return &n }
+	size := func(n int) *int { return &n }
+
+	d := models.EventDetails{
+		PrizePool: ptr(10000), PrizeCurrency: "USD",
+		Fee:         ptr(0),
+		TeamSizeMin: size(2), TeamSizeMax: size(4),
+	}
+	organizer := "Astana Hub"
+	if dropped := GroundDetails(&d, &organizer, post); len(dropped) != 0 {
+		t.Errorf("GroundDetails dropped %v from grounded details", dropped)
+	}
+
+	d = models.EventDetails{
+		PrizePool: ptr(50000), PrizeCurrency: "USD",
+		Fee: ptr(5000), FeeCurrency: "KZT",
+		TeamSizeMin: size(1), TeamSizeMax: size(5),
+	}
+	organizer = "Google"
+	dropped := GroundDetails(&d, &organizer, post)
+	want := []string{"prizePool", "fee", "teamSizeMin", "teamSizeMax", "organizer"}
+	if !slices.Equal(dropped, want) {
+		t.Errorf("GroundDetails dropped %v, want %v", dropped, want)
+	}
+	if d.PrizePool != nil || d.PrizeCurrency != "" || d.Fee != nil || d.FeeCurrency != "" || organizer != "" {
+		t.Errorf("GroundDetails kept ungrounded values: %+v, organizer %q", d, organizer)
+	}
+}
+
+func TestNameGrounded(t *testing.T) {
+	const post = "Организаторы: NU и Astana Hub при поддержке МЦРИАП РК"
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Astana Hub", true},
+		{"NU", true}, // short names must match as a whole word
+		{"МЦРИАП", true},
+		{"NUS", false},
+		{"Google", false},
+	}
+	for _, tt := range tests {
+		if got := NameGrounded(tt.name, post); got != tt.want {
+			t.Errorf("NameGrounded(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
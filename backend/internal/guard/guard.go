@@ -0,0 +1,80 @@
+// Package guard protects LLM prompts from untrusted text (Telegram posts, web
+// pages, user queries) and checks model output against that text.
+//
+// Untrusted text is placed between <untrusted_*> tags in the prompt
+// templates; Escape makes sure it cannot close those tags itself. Sanitize
+// removes lines that read like instructions to the model, and the grounding
+// checks reject extracted values that do not occur in the input.
+package guard
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Removed replaces a line dropped by Sanitize, so the model still sees that
+// something was there.
+const Removed = "[строка удалена: похожа на инструкцию для ИИ]"
+
+// tagRe matches anything resembling our delimiter tags, including variants
+// with spaces and different case.
+var tagRe = regexp.MustCompile(`(?i)<\s*/?\s*untrusted[\w-]*`)
+
+// pattern is an instruction-like phrase that has no business in an event
+// announcement.
+type pattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// Go's \b only knows ASCII word characters, so Cyrillic patterns rely on
+// explicit whitespace instead.
+var patterns = []pattern{
+	{"ignore-instructions", regexp.MustCompile(`(?i)\b(ignore|disregard|forget)\s+(all\s+|any\s+)?(the\s+|your\s+)?(previous\s+|prior\s+|above\s+|earlier\s+)?(instructions|prompts?|rules)`)},
+	{"ignore-instructions-ru", regexp.MustCompile(`(?i)(игнорируй|проигнорируй|забудь|не\s+следуй)\s+(все\s+|всё\s+)?(предыдущие\s+|прошлые\s+|эти\s+|свои\s+)?(инструкци|указани|правил|промпт)`)},
+	{"system-prompt", regexp.MustCompile(`(?i)(system\s+prompt|системн(ый|ого|ому)\s+промпт)`)},
+	{"role-override", regexp.MustCompile(`(?i)(\byou\s+are\s+now\b|\bact\s+as\s+(an?\s+)?(ai|assistant|model)\b|ты\s+теперь\s+)`)},
+	{"role-marker", regexp.MustCompile(`(?im)^\s*(system|assistant|user)\s*:`)},
+	{"status-override", regexp.MustCompile(`(?i)\b(return|respond\s+with|output|set|mark)\s+(the\s+)?status\s*(as\s+|to\s+|=|:)?\s*['"]?(live|dead)\b`)},
+	{"status-override-ru", regexp.MustCompile(`(?i)(верни|укажи|поставь|выстави|отметь)\s+(статус|status)\s*(как\s+|=|:|-|—)?\s*['"«]?\s*(live|dead)`)},
+	{"answer-override", regexp.MustCompile(`(?i)\b(answer|respond|reply)\s+(only\s+)?(with\s+)?['"]?(yes|no)\b`)},
+	{"answer-override-ru", regexp.MustCompile(`(?i)(ответь|отвечай|ответьте)\s+(только\s+)?['"«]?\s*(yes|no|да|нет)([^\p{L}]|$)`)},
+	{"delimiter", tagRe},
+}
+
+// Escape neutralizes delimiter tags inside untrusted text by replacing their
+// "<" with a look-alike character.
+func Escape(text string) string {
+	return tagRe.ReplaceAllStringFunc(text, func(m string) string {
+		return strings.ReplaceAll(m, "<", "‹")
+	})
+}
+
+// Detect returns the names of the instruction-like patterns found in text.
+func Detect(text string) []string {
+	var found []string
+	for _, p := range patterns {
+		if p.re.MatchString(text) {
+			found = append(found, p.name)
+		}
+	}
+	return found
+}
+
+// Sanitize replaces every line containing an instruction-like pattern with
+// Removed and returns the names of the patterns found. Text without findings
+// is returned unchanged.
+func Sanitize(text string) (string, []string) {
+	found := Detect(text)
+	if len(found) == 0 {
+		return text, nil
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if len(Detect(line)) > 0 {
+			lines[i] = Removed
+		}
+	}
+	return strings.Join(lines, "\n"), found
+}
@@ -0,0 +1,442 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"hackflow-api/internal/database"
+	"hackflow-api/internal/models"
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/taxonomy"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AdminHandler serves the moderation endpoints under /api/admin.
+type AdminHandler struct {
+	DB *gorm.DB
+	// Regions normalizes cities edited by moderators
+	Regions *region.Registry
+	// Tags validates tags edited by moderators
+	Tags *taxonomy.Taxonomy
+}
+
+// NewAdminHandler creates a new AdminHandler with the given database connection
+func NewAdminHandler(db *gorm.DB, regions *region.Registry, tags *taxonomy.Taxonomy) *AdminHandler {
+	return &AdminHandler{
+		DB:      db,
+		Regions: regions,
+		Tags:    tags,
+	}
+}
+
+// HackathonUpdate is the body of PATCH /api/admin/hackathons/:id. Only fields
+// present in the request are changed; an empty deadline clears it, as does 0
+// for minAge, maxAge, prizePool and the team sizes, and a negative fee. Cities
+// accepts slugs or names and replaces all host cities, Tags likewise replaces
+// all tags; an empty organizer unlinks it.
+type HackathonUpdate struct {
+	Title     *string   `json:"title"`
+	Date      *string   `json:"date"`
+	Deadline  *string   `json:"deadline"`
+	Format    *string   `json:"format"`
+	City      *string   `json:"city"`
+	MinAge    *int      `json:"minAge"`
+	MaxAge    *int      `json:"maxAge"`
+	Audiences *[]string `json:"audiences"`
+	Link      *string   `json:"link"`
+	Status    *string   `json:"status"`
+	Cities    *[]string `json:"cities"`
+	National  *bool     `json:"national"`
+
+	PrizePool     *int64    `json:"prizePool"`
+	PrizeCurrency *string   `json:"prizeCurrency"`
+	Tracks        *[]string `json:"tracks"`
+	TeamSizeMin   *int      `json:"teamSizeMin"`
+	TeamSizeMax   *int      `json:"teamSizeMax"`
+	Fee           *int64    `json:"fee"`
+	FeeCurrency   *string   `json:"feeCurrency"`
+	Language      *string   `json:"language"`
+	Organizer     *string   `json:"organizer"`
+	Tags          *[]string `json:"tags"`
+}
+
+// ListHackathons handles GET /api/admin/hackathons?status=pending
+func (h *AdminHandler) ListHackathons(c *gin.Context) {
+	status := c.DefaultQuery("status", models.ModerationPending)
+	if !isModerationStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown moderation status"})
+		return
+	}
+
+	var hackathons []models.Hackathon
+	if err := h.DB.WithContext(c.Request.Context()).
+		Preload("Cities").Preload("Organizer").Preload("Tags").
+		Where("moderation_status = ?", status).
+		Order("created_at DESC").
+		Find(&hackathons).Error; err != nil {
+		slog.Error("Failed to list hackathons for moderation", "error", err, "status", status)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data"})
+		return
+	}
+
+	c.JSON(http.StatusOK, hackathons)
+}
+
+// UpdateHackathon handles PATCH /api/admin/hackathons/:id
+func (h *AdminHandler) UpdateHackathon(c *gin.Context) {
+	hackathon, ok := h.load(c)
+	if !ok {
+		return
+	}
+
+	var req HackathonUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if err := applyUpdate(hackathon, req, h.Regions); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var tagSlugs []string
+	if req.Tags != nil {
+		slugs, unknown := h.resolveTags(*req.Tags)
+		if unknown != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown tag %q", unknown), "tags": h.Tags.Slugs()})
+			return
+		}
+		tagSlugs = slugs
+	}
+
+	// Города, организатор и теги пишутся в одной транзакции с самой записью,
+	// чтобы неудачное сохранение не оставляло ее обновленной наполовину
+	h.save(c, hackathon, func(tx *gorm.DB) error {
+		if req.City != nil || req.Cities != nil || req.National != nil {
+			if err := h.relinkCities(tx, hackathon, req); err != nil {
+				return err
+			}
+		}
+		if req.Organizer != nil {
+			if err := setOrganizer(tx, hackathon, *req.Organizer); err != nil {
+				return err
+			}
+		}
+		if req.Tags != nil {
+			return retag(tx, hackathon, tagSlugs)
+		}
+		return nil
+	})
+}
+
+// ApproveHackathon handles POST /api/admin/hackathons/:id/approve
+func (h *AdminHandler) ApproveHackathon(c *gin.Context) {
+	h.setModerationStatus(c, models.ModerationApproved)
+}
+
+// RejectHackathon handles POST /api/admin/hackathons/:id/reject
+func (h *AdminHandler) RejectHackathon(c *gin.Context) {
+	h.setModerationStatus(c, models.ModerationRejected)
+}
+
+func (h *AdminHandler) setModerationStatus(c *gin.Context, status string) {
+	hackathon, ok := h.load(c)
+	if !ok {
+		return
+	}
+
+	hackathon.ModerationStatus = status
+	if h.save(c, hackathon, nil) {
+		slog.Info("Hackathon moderated", "id", hackathon.ID, "title", hackathon.Title, "status", status)
+	}
+}
+
+func (h *AdminHandler) load(c *gin.Context) (*models.Hackathon, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hackathon id"})
+		return nil, false
+	}
+
+	var hackathon models.Hackathon
+	err = h.DB.WithContext(c.Request.Context()).Preload("Cities").Preload("Organizer").Preload("Tags").First(&hackathon, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Hackathon not found"})
+		return nil, false
+	}
+	if err != nil {
+		slog.Error("Failed to load hackathon", "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data"})
+		return nil, false
+	}
+
+	return &hackathon, true
+}
+
+// relinkCities replaces the host cities after a change of city, cities or the
+// national flag. Without an explicit cities list they are derived from the
+// city text.
+func (h *AdminHandler) relinkCities(tx *gorm.DB, hackathon *models.Hackathon, req HackathonUpdate) error {
+	names := []string{hackathon.City}
+	if req.Cities != nil {
+		names = *req.Cities
+	}
+
+	cities, err := database.FindCities(tx, h.Regions.CitySlugs(names, hackathon.National))
+	if err == nil {
+		err = tx.Model(hackathon).Association("Cities").Replace(cities)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update cities: %w", err)
+	}
+	hackathon.Cities = cities
+	return nil
+}
+
+// resolveTags resolves the tags edited by a moderator. Every name must be a slug
+// or name of the taxonomy, so moderators cannot grow the controlled list by
+// accident; the first unknown name is returned.
+func (h *AdminHandler) resolveTags(names []string) (slugs []string, unknown string) {
+	slugs = make([]string, 0, len(names))
+	for _, name := range names {
+		tag, ok := h.Tags.Find(name)
+		if !ok {
+			return nil, name
+		}
+		slugs = append(slugs, tag.Slug)
+	}
+	return slugs, ""
+}
+
+// retag replaces the tags of a hackathon and marks them as edited by a
+// moderator.
+func retag(tx *gorm.DB, hackathon *models.Hackathon, slugs []string) error {
+	tags, err := database.FindTags(tx, slugs)
+	if err == nil {
+		err = tx.Model(hackathon).Association("Tags").Replace(tags)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update tags: %w", err)
+	}
+	now := time.Now()
+	hackathon.Tags, hackathon.TagsEditedAt = tags, &now
+	return nil
+}
+
+// setOrganizer links the organizer with this name, creating it if needed; an
+// empty name unlinks the current one.
+func setOrganizer(tx *gorm.DB, hackathon *models.Hackathon, name string) error {
+	org, err := database.FindOrCreateOrganizer(tx, name)
+	if err != nil {
+		return fmt.Errorf("failed to save organizer %q: %w", name, err)
+	}
+	hackathon.Organizer = org
+	hackathon.OrganizerID = nil
+	if org != nil {
+		hackathon.OrganizerID = &org.ID
+	}
+	return nil
+}
+
+// save stores the hackathon after the association writes of update, if any,
+// in one transaction, and responds with the saved record.
+func (h *AdminHandler) save(c *gin.Context, hackathon *models.Hackathon, update func(tx *gorm.DB) error) bool {
+	err := h.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if update != nil {
+			if err := update(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Save(hackathon).Error
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		c.JSON(http.StatusConflict, gin.H{"error": "A hackathon with this title already exists"})
+		return false
+	}
+	if err != nil {
+		slog.Error("Failed to save hackathon", "error", err, "id", hackathon.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save data"})
+		return false
+	}
+
+	c.JSON(http.StatusOK, hackathon)
+	return true
+}
+
+// applyUpdate copies the fields present in req. A known city is stored under
+// its canonical name in its region's language; host cities are relinked by
+// relinkCities.
+func applyUpdate(h *models.Hackathon, req HackathonUpdate, regions *region.Registry) error {
+	if req.Title != nil {
+		if models.NormalizeTitle(*req.Title) == "" {
+			return errors.New("title must not be empty")
+		}
+		h.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Date != nil {
+		h.Date = *req.Date
+	}
+	if req.Deadline != nil {
+		if *req.Deadline == "" {
+			h.Deadline = nil
+		} else {
+			d, err := time.Parse("2006-01-02", *req.Deadline)
+			if err != nil {
+				return errors.New("deadline must have the form YYYY-MM-DD")
+			}
+			h.Deadline = &d
+		}
+	}
+	if req.Format != nil {
+		format := models.Format(*req.Format)
+		if !format.Valid() {
+			return errors.New("format must be offline, online or hybrid")
+		}
+		h.Format = format
+	}
+	if req.City != nil {
+		h.City = strings.TrimSpace(*req.City)
+		if city, ok := regions.FindCity(h.City); ok {
+			if loc, err := regions.Resolve(city.Region, ""); err == nil {
+				h.City = city.Name(loc.Lang)
+			}
+		}
+	}
+	if req.National != nil {
+		h.National = *req.National
+	}
+	if req.MinAge != nil {
+		h.MinAge = ageBound(*req.MinAge)
+	}
+	if req.MaxAge != nil {
+		h.MaxAge = ageBound(*req.MaxAge)
+	}
+	if h.MinAge != nil && h.MaxAge != nil && *h.MinAge > *h.MaxAge {
+		return errors.New("minAge must not exceed maxAge")
+	}
+	if req.Audiences != nil {
+		audiences, err := parseAudiences(*req.Audiences)
+		if err != nil {
+			return err
+		}
+		h.Audiences = audiences
+	}
+	if req.Link != nil {
+		h.Link = *req.Link
+	}
+	if req.Status != nil {
+		if *req.Status != "LIVE" && *req.Status != "DEAD" {
+			return errors.New("status must be LIVE or DEAD")
+		}
+		h.Status = *req.Status
+	}
+	return applyDetails(&h.EventDetails, req)
+}
+
+// applyDetails copies the prize, track, team, fee and language fields present
+// in req, rejecting unknown currencies and language codes.
+func applyDetails(d *models.EventDetails, req HackathonUpdate) error {
+	if req.PrizePool != nil {
+		d.PrizePool = nil
+		if *req.PrizePool > 0 {
+			d.PrizePool = req.PrizePool
+		}
+	}
+	if req.PrizeCurrency != nil {
+		currency := models.ParseCurrency(*req.PrizeCurrency)
+		if currency == "" && *req.PrizeCurrency != "" {
+			return errors.New("prizeCurrency must be a currency code such as KZT or USD")
+		}
+		d.PrizeCurrency = currency
+	}
+	if req.Tracks != nil {
+		d.Tracks = *req.Tracks
+	}
+	if req.TeamSizeMin != nil {
+		size, err := teamSizeBound(*req.TeamSizeMin)
+		if err != nil {
+			return err
+		}
+		d.TeamSizeMin = size
+	}
+	if req.TeamSizeMax != nil {
+		size, err := teamSizeBound(*req.TeamSizeMax)
+		if err != nil {
+			return err
+		}
+		d.TeamSizeMax = size
+	}
+	if d.TeamSizeMin != nil && d.TeamSizeMax != nil && *d.TeamSizeMin > *d.TeamSizeMax {
+		return errors.New("teamSizeMin must not exceed teamSizeMax")
+	}
+	if req.Fee != nil {
+		d.Fee = nil
+		if *req.Fee >= 0 {
+			d.Fee = req.Fee
+		}
+	}
+	if req.FeeCurrency != nil {
+		currency := models.ParseCurrency(*req.FeeCurrency)
+		if currency == "" && *req.FeeCurrency != "" {
+			return errors.New("feeCurrency must be a currency code such as KZT or USD")
+		}
+		d.FeeCurrency = currency
+	}
+	if req.Language != nil {
+		d.Language = *req.Language
+	}
+
+	language := d.Language
+	d.Normalize()
+	if d.Language != strings.ToLower(strings.TrimSpace(language)) {
+		return errors.New("language must be a two-letter code such as ru or en")
+	}
+	return nil
+}
+
+// ageBound treats 0 or a negative age as no bound.
+func ageBound(age int) *int {
+	if age <= 0 {
+		return nil
+	}
+	return &age
+}
+
+// teamSizeBound treats 0 or a negative team size as no bound and rejects
+// sizes above the cap Normalize applies to model output, which would
+// otherwise be dropped silently.
+func teamSizeBound(size int) (*int, error) {
+	if size > models.MaxTeamSize {
+		return nil, fmt.Errorf("team size must not exceed %d", models.MaxTeamSize)
+	}
+	if size <= 0 {
+		return nil, nil
+	}
+	return &size, nil
+}
+
+// parseAudiences validates a set of audiences; an empty list means anyone.
+func parseAudiences(values []string) ([]models.Audience, error) {
+	var audiences []models.Audience
+	for _, v := range values {
+		audience := models.Audience(strings.TrimSpace(v))
+		if !audience.Valid() {
+			return nil, errors.New("audiences must be school, students or professionals")
+		}
+		if !slices.Contains(audiences, audience) {
+			audiences = append(audiences, audience)
+		}
+	}
+	return audiences, nil
+}
+
+func isModerationStatus(s string) bool {
+	return s == models.ModerationPending || s == models.ModerationApproved || s == models.ModerationRejected
+}
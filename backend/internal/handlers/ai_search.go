@@ -1,64 +1,128 @@
 package handlers
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 
 	"hackflow-api/internal/config"
+	"hackflow-api/internal/guard"
+	"hackflow-api/internal/llmusage"
 	"hackflow-api/internal/models"
+	"hackflow-api/internal/pagefetch"
+	"hackflow-api/internal/prompts"
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/taxonomy"
+	"hackflow-api/internal/websearch"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/option"
+	"gorm.io/gorm"
 )
 
-// SearchAIHandler содержит зависимости для агента (ключи)
+// SearchAIHandler содержит зависимости для агента (клиент Gemini, провайдер
+// веб-поиска и БД для очереди модерации)
 type SearchAIHandler struct {
 	Config *config.Config
+	DB     *gorm.DB
+	// Gemini — общий для всех запросов клиент (nil, если не задан GEMINI_API_KEY)
+	Gemini   *genai.Client
+	Searcher websearch.WebSearcher
+	// Pages подгружает полный текст лучших результатов (nil — только сниппеты)
+	Pages *pagefetch.Fetcher
+	// Cache хранит готовые ответы на одинаковые запросы (nil — без кэша)
+	Cache *SearchCache
+	// Regions — регионы поиска и язык ответа (параметры region и lang)
+	Regions *region.Registry
+	// Usage учитывает токены Gemini и дневной бюджет поиска (nil — без учета)
+	Usage *llmusage.Tracker
+	// Tags — справочник тематик, из которого модель выбирает теги (nil — без тегов)
+	Tags *taxonomy.Taxonomy
 }
 
-func NewSearchAIHandler(cfg *config.Config) *SearchAIHandler {
+// searchModel — модель Gemini, которой агент извлекает мероприятия
+const searchModel = "gemini-2.5-flash"
+
+func NewSearchAIHandler(cfg *config.Config, db *gorm.DB, gemini *genai.Client, searcher websearch.WebSearcher, pages *pagefetch.Fetcher, searchCache *SearchCache, regions *region.Registry, usage *llmusage.Tracker, tags *taxonomy.Taxonomy) *SearchAIHandler {
 	return &SearchAIHandler{
-		Config: cfg,
+		Config:   cfg,
+		DB:       db,
+		Gemini:   gemini,
+		Searcher: searcher,
+		Pages:    pages,
+		Cache:    searchCache,
+		Regions:  regions,
+		Usage:    usage,
+		Tags:     tags,
 	}
 }
 
-// TavilyRequest описывает тело запроса к API Tavily
-type TavilyRequest struct {
-	APIKey        string `json:"api_key"`
-	Query         string `json:"query"`
-	SearchDepth   string `json:"search_depth"`
-	IncludeAnswer bool   `json:"include_answer"`
-	MaxResults    int    `json:"max_results"`
+// AIHackathon — облегченная структура для ответов от ИИ (без time.Time)
+type AIHackathon struct {
+	Title string `json:"title"`
+	// SourceTitle — название дословно из источника; Title может быть переведен
+	SourceTitle string  `json:"sourceTitle,omitempty"`
+	Date        string  `json:"date"`
+	Deadline    *string `json:"deadline"`
+	Format      string  `json:"format"`
+	City        string  `json:"city"`
+	AgeLimit    string  `json:"ageLimit"`
+	Link        *string `json:"link"`
+	Status      string  `json:"status"`
+	// Cities — все города проведения, National — общенациональный ивент
+	Cities   []string `json:"cities,omitempty"`
+	National bool     `json:"national"`
+	// CitySlugs — города из справочника (заполняется сервером)
+	CitySlugs []string `json:"citySlugs,omitempty"`
+	// MinAge, MaxAge и Audiences разбираются сервером из ageLimit
+	MinAge    *int              `json:"minAge"`
+	MaxAge    *int              `json:"maxAge"`
+	Audiences []models.Audience `json:"audiences"`
+	// Призы, треки, размер команды, взнос и язык; Organizer — название организатора
+	models.EventDetails
+	Organizer string `json:"organizer"`
+	// Tags — slug тематик из справочника (сервер отбрасывает неизвестные)
+	Tags []string `json:"tags"`
+	// Citations — номера результатов веб-поиска (с 1), на которые сослалась модель
+	Citations []int `json:"citations,omitempty"`
+	// Sources и Verified заполняются сервером: процитированные страницы и флаг
+	// того, что название и даты действительно встречаются в их тексте
+	Sources  []Source `json:"sources"`
+	Verified bool     `json:"verified"`
+	// SourceURLs — страницы, из которых извлечено событие (для модерации)
+	SourceURLs []string `json:"-"`
+	// PromptVersion — версия шаблона промпта, которым извлечено событие
+	PromptVersion string `json:"-"`
 }
 
-// TavilyResponse описывает ответ от API Tavily
-type TavilyResponse struct {
-	Results []struct {
-		Content string `json:"content"`
-	} `json:"results"`
+// Source — страница веб-поиска, процитированная для события
+type Source struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
 }
 
-// AIHackathon — облегченная структура для ответов от ИИ (без time.Time)
-type AIHackathon struct {
-	Title    string  `json:"title"`
-	Date     string  `json:"date"`
-	Deadline *string `json:"deadline"`
-	Format   string  `json:"format"`
-	City     string  `json:"city"`
-	AgeLimit string  `json:"ageLimit"`
-	Link     *string `json:"link"`
-	Status   string  `json:"status"`
+// searchError описывает ошибку этапа поиска вместе с HTTP-статусом для клиента
+type searchError struct {
+	Status  int
+	Message string
+	Stage   string
+	Err     error
+}
+
+func (e *searchError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Stage, e.Err)
 }
 
-// SearchAI выполняет поиск в реальном времени через Tavily + Gemini
+func (e *searchError) Unwrap() error {
+	return e.Err
+}
+
+// SearchAI выполняет поиск в реальном времени через веб-поиск + Gemini
 func (h *SearchAIHandler) SearchAI(c *gin.Context) {
 	query := strings.TrimSpace(c.Query("q"))
 	if query == "" {
@@ -66,119 +130,212 @@ func (h *SearchAIHandler) SearchAI(c *gin.Context) {
 		return
 	}
 
-	slog.Info("Starting Web-Browsing RAG Search", "query", query)
+	loc, ok := h.resolveLocale(c)
+	if !ok {
+		return
+	}
+
+	// Общий дедлайн запроса (middleware.Timeout) и отмена при отключении клиента
+	ctx := c.Request.Context()
 
-	if h.Config.TavilyAPIKey == "" || h.Config.GeminiAPIKey == "" {
-		slog.Error("Missing API keys for AI Search")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Server misconfigured: missing API keys"})
+	// refresh=true — принудительно идем в веб мимо кэша
+	refresh := c.Query("refresh") == "true"
+
+	hackathons, cacheStatus, expiresAt, err := h.CachedSearch(ctx, query, loc, refresh)
+	if errors.Is(err, llmusage.ErrBudgetExceeded) {
+		h.respondFromDB(c, query)
+		return
+	}
+	if err != nil {
+		respondSearchError(c, ctx, err)
 		return
 	}
+	setCacheHeaders(c, cacheStatus, expiresAt)
+
+	// Возвращаем результаты (кодом 200). В публичную выдачу они попадут только после модерации
+	c.JSON(http.StatusOK, hackathons)
+}
+
+// Search запускает веб-агента: поиск в интернете и извлечение мероприятий через Gemini.
+// Ошибки возвращаются как *searchError.
+func (h *SearchAIHandler) Search(ctx context.Context, query string, loc region.Locale) ([]AIHackathon, error) {
+	slog.Info("Starting Web-Browsing RAG Search", "query", query, "locale", loc.Key())
 
-	// 1. Поиск в интернете через Tavily
-	reqBody := TavilyRequest{
-		APIKey:        h.Config.TavilyAPIKey,
-		Query:         fmt.Sprintf("Hackathons IT events in Kazakhstan %s", query),
-		SearchDepth:   "advanced",
-		IncludeAnswer: false,
-		MaxResults:    5,
+	// Дневной бюджет токенов исчерпан — не тратим и запрос к веб-поиску
+	if err := h.Usage.Allow(ctx, llmusage.CallerSearch); err != nil {
+		return nil, &searchError{Status: http.StatusServiceUnavailable, Message: "AI search is temporarily unavailable", Stage: "budget", Err: err}
 	}
 
-	jsonBytes, err := json.Marshal(reqBody)
+	// 1. Поиск в интернете через выбранного провайдера (Tavily, SearXNG или фикстура)
+	results, err := h.searchWeb(ctx, query, loc)
 	if err != nil {
-		slog.Error("Failed to marshal Tavily request", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal error formatting search via Tavily"})
-		return
+		return nil, err
 	}
+	if len(results) == 0 {
+		return []AIHackathon{}, nil
+	}
+
+	// 2. Анализ данных через Gemini 2.5 Flash
+	model := h.newSearchModel()
 
-	resp, err := http.Post("https://api.tavily.com/search", "application/json", bytes.NewBuffer(jsonBytes))
+	prompt, err := buildSearchPrompt(query, results, loc, h.Tags.Slugs())
 	if err != nil {
-		slog.Error("Failed to reach Tavily API", "error", err)
-		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to search the web"})
-		return
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		slog.Error("Tavily returned error", "status", resp.StatusCode, "body", string(body))
-		c.JSON(http.StatusBadGateway, gin.H{"error": "Web search provider returned an error"})
-		return
+	slog.Debug("Sending aggregated results to Gemini...")
+	aiResp, err := model.GenerateContent(ctx, genai.Text(prompt.Text))
+	h.Usage.Record(ctx, llmusage.CallerSearch, searchModel, llmusage.FromResponse(aiResp))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, &searchError{Stage: "AI analysis", Err: ctx.Err()}
+		}
+		slog.Error("Failed to generate content via Gemini", "error", err)
+		return nil, &searchError{Status: http.StatusInternalServerError, Message: "Failed to process search results via AI", Stage: "AI analysis", Err: err}
 	}
 
-	var tavilyResp TavilyResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tavilyResp); err != nil {
-		slog.Error("Failed to decode Tavily response", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error parsing web search results"})
-		return
+	if len(aiResp.Candidates) == 0 || len(aiResp.Candidates[0].Content.Parts) == 0 {
+		return []AIHackathon{}, nil
 	}
 
-	if len(tavilyResp.Results) == 0 {
-		slog.Info("No search results found from Tavily for prompt", "query", query)
-		c.JSON(http.StatusOK, []models.Hackathon{})
-		return
+	rawString := fmt.Sprintf("%v", aiResp.Candidates[0].Content.Parts[0])
+	slog.Info("Raw Gemini Response", "data", rawString)
+
+	hackathons, err := parseAIHackathons(rawString)
+	if err != nil {
+		return nil, &searchError{Status: http.StatusInternalServerError, Message: "Failed to parse AI response", Stage: "AI analysis", Err: err}
 	}
+	attachSources(hackathons, results, loc.Lang)
+	normalizeHackathons(hackathons, loc, h.Tags)
+	for i := range hackathons {
+		hackathons[i].PromptVersion = prompt.Version
+	}
+
+	slog.Info("AI Search completed successfully", "results_count", len(hackathons))
+
+	// Сохраняем находки в очередь модерации (status=pending)
+	savePending(ctx, h.DB, hackathons)
+
+	return hackathons, nil
+}
 
-	var webContextBuilder strings.Builder
-	for i, res := range tavilyResp.Results {
-		webContextBuilder.WriteString(fmt.Sprintf("\n--- РЕЗУЛЬТАТ %d ---\n%s", i+1, res.Content))
+// searchWeb выполняет веб-поиск и переводит ошибки провайдера в *searchError
+func (h *SearchAIHandler) searchWeb(ctx context.Context, query string, loc region.Locale) ([]websearch.Result, error) {
+	if h.Gemini == nil {
+		slog.Error("Missing API keys for AI Search")
+		return nil, &searchError{Status: http.StatusInternalServerError, Message: "Server misconfigured: missing API keys", Stage: "configuration", Err: errors.New("missing GEMINI_API_KEY")}
 	}
-	webContext := webContextBuilder.String()
 
-	// 2. Анализ данных через Gemini 2.5 Flash
-	ctx := context.Background()
-	client, err := genai.NewClient(ctx, option.WithAPIKey(h.Config.GeminiAPIKey))
+	webQuery, err := loc.Region.WebQuery(query)
 	if err != nil {
-		slog.Error("Failed to initialize Gemini client", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to initialize AI processor"})
-		return
+		return nil, &searchError{Status: http.StatusInternalServerError, Message: "Server misconfigured: invalid region", Stage: "configuration", Err: err}
 	}
-	defer client.Close()
 
-	model := client.GenerativeModel("gemini-2.5-flash")
+	results, err := h.Searcher.Search(ctx, webQuery, 5)
+	if err != nil {
+		var statusErr *websearch.StatusError
+		switch {
+		case ctx.Err() != nil:
+			return nil, &searchError{Stage: "web search", Err: ctx.Err()}
+		case errors.Is(err, websearch.ErrNotConfigured):
+			slog.Error("Web search provider is not configured", "provider", h.Searcher.Name(), "error", err)
+			return nil, &searchError{Status: http.StatusInternalServerError, Message: "Server misconfigured: missing API keys", Stage: "web search", Err: err}
+		case errors.As(err, &statusErr):
+			slog.Error("Web search provider returned error", "provider", statusErr.Provider, "status", statusErr.Code, "body", statusErr.Body)
+			return nil, &searchError{Status: http.StatusBadGateway, Message: "Web search provider returned an error", Stage: "web search", Err: err}
+		default:
+			slog.Error("Failed to search the web", "provider", h.Searcher.Name(), "error", err)
+			return nil, &searchError{Status: http.StatusBadGateway, Message: "Failed to search the web", Stage: "web search", Err: err}
+		}
+	}
+
+	if len(results) == 0 {
+		slog.Info("No web search results found for prompt", "query", query, "provider", h.Searcher.Name())
+		return results, nil
+	}
+
+	// Сниппеты короткие и часто без дат и ссылок — подгружаем страницы целиком
+	if h.Pages != nil {
+		results = h.Pages.Enrich(ctx, results)
+	}
+
+	// Страницы — недоверенный текст: вырезаем строки, похожие на инструкции для ИИ
+	for i := range results {
+		title, inTitle := guard.Sanitize(results[i].Title)
+		content, inContent := guard.Sanitize(results[i].Content)
+		results[i].Title, results[i].Content = title, content
+		if found := append(inTitle, inContent...); len(found) > 0 {
+			slog.Warn("Prompt injection attempt in web search result", "url", results[i].URL, "patterns", found)
+		}
+	}
+	return results, nil
+}
+
+// newSearchModel настраивает модель для извлечения мероприятий на общем клиенте
+func (h *SearchAIHandler) newSearchModel() *genai.GenerativeModel {
+	model := h.Gemini.GenerativeModel(searchModel)
 	model.SetTemperature(0.2)
 	model.ResponseMIMEType = "application/json"
+	return model
+}
 
-	currentDate := time.Now().Format("2006-01-02")
-	prompt := fmt.Sprintf(`Сегодняшняя дата: %s.
-Пользователь ищет: '%s'.
-Вот сырые тексты из интернета (они могут быть на английском или русском):
-%s
-
-Твоя задача — извлечь IT-мероприятия. ВАЖНЫЕ ПРАВИЛА ДЛЯ КАЗАХСТАНА:
-- Если ивент имеет статус 'National' (Национальный) или проходит в '20+ cities' (например, Decentrathon), АВТОМАТИЧЕСКИ считай, что он проходит в Астане и Алматы. Обязательно добавляй его в ответ!
-- Переводи названия городов на русский (Astana -> Астана, Almaty -> Алматы).
-- Если точных дат нет, пиши 'Даты уточняются'.
-- Если текст на английском, переведи суть и верни JSON на русском.
-- Если ничего не найдено, верни пустой массив [].
-
-Верни массив JSON. Структура одного объекта:
-- title (строка, на русском)
-- date (строка, на русском)
-- deadline (строка формата YYYY-MM-DD или null)
-- format (строка: строго ОФЛАЙН или ОНЛАЙН, или ОФЛАЙН/ОНЛАЙН)
-- city (строка на русском или null)
-- ageLimit (строка, например "Нет ограничений")
-- link (строка URL или null)
-- status (строка: LIVE если дедлайн не прошел относительно сегодняшней даты, иначе DEAD)
-
-Только чистый JSON массив.`, currentDate, query, webContext)
-
-	slog.Debug("Sending aggregated results to Gemini...")
-	aiResp, err := model.GenerateContent(ctx, genai.Text(prompt))
+// buildSearchPrompt собирает промпт из запроса пользователя, результатов
+// веб-поиска, правил региона и списка тематик (шаблон
+// internal/prompts/templates/search.tmpl)
+func buildSearchPrompt(query string, results []websearch.Result, loc region.Locale, tags []string) (prompts.Prompt, error) {
+	prompt, err := prompts.Search(prompts.SearchData{
+		Date:    time.Now().Format("2006-01-02"),
+		Query:   query,
+		Results: results,
+		Locale:  loc,
+		Tags:    tags,
+	})
 	if err != nil {
-		slog.Error("Failed to generate content via Gemini", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process search results via AI"})
-		return
+		slog.Error("Failed to render search prompt", "error", err, "locale", loc.Key())
+		return prompts.Prompt{}, &searchError{Status: http.StatusInternalServerError, Message: "Failed to prepare AI request", Stage: "AI analysis", Err: err}
 	}
+	slog.Debug("Search prompt sent to Gemini", "version", prompt.Version, "prompt", prompt.Text)
+	return prompt, nil
+}
 
-	if len(aiResp.Candidates) == 0 || len(aiResp.Candidates[0].Content.Parts) == 0 {
-		c.JSON(http.StatusOK, []models.Hackathon{})
-		return
+// normalizeHackathons приводит названия городов к написанию региона (Astana ->
+// Астана), проставляет города проведения из справочника, разбирает формат и
+// возрастные ограничения, отбрасывает неправдоподобные призы и размеры команд
+// и оставляет только теги из справочника (дополняя их по ключевым словам)
+func normalizeHackathons(hackathons []AIHackathon, loc region.Locale, tags *taxonomy.Taxonomy) {
+	for i := range hackathons {
+		normalizeHackathon(&hackathons[i], loc, tags)
 	}
+}
 
-	rawString := fmt.Sprintf("%v", aiResp.Candidates[0].Content.Parts[0])
-	slog.Info("Raw Gemini Response", "data", rawString)
+func normalizeHackathon(h *AIHackathon, loc region.Locale, tags *taxonomy.Taxonomy) {
+	if format := models.ParseFormat(h.Format); format != "" {
+		h.Format = string(format)
+	}
+	h.MinAge, h.MaxAge, h.Audiences = models.ParseAgeLimit(h.AgeLimit)
+	h.EventDetails.Normalize()
+	h.Organizer = strings.TrimSpace(h.Organizer)
+	h.Tags = tags.Assign(h.Tags, append([]string{h.Title}, h.Tracks...)...)
+
+	h.City, _ = loc.City(h.City)
+	for i, name := range h.Cities {
+		h.Cities[i], _ = loc.City(name)
+	}
+	h.CitySlugs = loc.CitySlugs(append([]string{h.City}, h.Cities...), h.National)
+}
 
+// resolveLocale читает параметры region и lang; при неизвестном коде отвечает 400
+func (h *SearchAIHandler) resolveLocale(c *gin.Context) (region.Locale, bool) {
+	loc, err := h.Regions.Resolve(strings.TrimSpace(c.Query("region")), strings.TrimSpace(c.Query("lang")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "regions": h.Regions.Codes()})
+		return region.Locale{}, false
+	}
+	return loc, true
+}
+
+// parseAIHackathons очищает ответ модели от Markdown и декодирует массив мероприятий
+func parseAIHackathons(rawString string) ([]AIHackathon, error) {
 	// Очистка от Markdown
 	jsonText := strings.TrimSpace(rawString)
 	jsonText = strings.ReplaceAll(jsonText, "```json", "")
@@ -192,19 +349,91 @@ func (h *SearchAIHandler) SearchAI(c *gin.Context) {
 		}
 	}
 
-	slog.Debug("Tavily context sent to Gemini", "webContext", webContext)
 	slog.Debug("Cleaned JSON ready for parsing", "jsonText", jsonText)
 
 	// Декодируем в облегченную структуру (без time.Time для deadline)
 	var hackathons []AIHackathon
 	if err := json.Unmarshal([]byte(jsonText), &hackathons); err != nil {
 		slog.Error("JSON Unmarshal failed", "error", err, "raw_json", jsonText)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse AI response"})
+		return nil, err
+	}
+	return hackathons, nil
+}
+
+// attachSources заменяет номера цитат на сами источники и проверяет, что
+// название и даты события встречаются в процитированном тексте; lang — язык
+// ответа, на который модель могла перевести название
+func attachSources(hackathons []AIHackathon, results []websearch.Result, lang string) {
+	for i := range hackathons {
+		attachSource(&hackathons[i], results, lang)
+	}
+}
+
+func attachSource(h *AIHackathon, results []websearch.Result, lang string) {
+	h.Sources = []Source{}
+	h.SourceURLs = nil
+
+	var cited []websearch.Result
+	seen := make(map[int]bool)
+	for _, idx := range h.Citations {
+		if idx < 1 || idx > len(results) || seen[idx] {
+			slog.Debug("Dropping invalid citation", "title", h.Title, "citation", idx)
+			continue
+		}
+		seen[idx] = true
+		res := results[idx-1]
+		cited = append(cited, res)
+		h.Sources = append(h.Sources, Source{Title: res.Title, URL: res.URL})
+		if res.URL != "" {
+			h.SourceURLs = append(h.SourceURLs, res.URL)
+		}
+	}
+
+	h.Verified = verifyGrounding(*h, cited)
+	checkOutput(h, cited, lang, time.Now())
+}
+
+// respondFromDB отвечает только одобренными мероприятиями из БД, когда дневной
+// бюджет токенов на поиск исчерпан. Заголовок X-Web-Search: budget-exceeded
+// сообщает клиенту, что веб-агент не запускался.
+func (h *SearchAIHandler) respondFromDB(c *gin.Context, query string) {
+	local, err := findHackathons(h.DB.WithContext(c.Request.Context()), HackathonFilter{Query: query})
+	if err != nil {
+		slog.Error("Failed to search hackathons", "error", err, "query", query)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search data"})
 		return
 	}
 
-	slog.Info("AI Search completed successfully", "results_count", len(hackathons))
+	results := make([]SearchResult, 0, len(local))
+	for _, hackathon := range local {
+		results = append(results, resultFromHackathon(hackathon))
+	}
 
-	// Возвращаем результаты (кодом 200). В БД не сохраняем!
-	c.JSON(http.StatusOK, hackathons)
+	slog.Info("AI search budget exceeded, answered from database", "query", query, "results", len(results))
+	c.Header("X-Web-Search", webSearchBudgetExceeded)
+	c.JSON(http.StatusOK, results)
+}
+
+// respondSearchError отвечает 504, если истек общий дедлайн запроса, молча
+// прерывает обработку, если клиент отключился, а иначе отдает статус из searchError.
+func respondSearchError(c *gin.Context, ctx context.Context, err error) {
+	stage := "search"
+	var se *searchError
+	if errors.As(err, &se) {
+		stage = se.Stage
+	}
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		slog.Warn("AI search deadline exceeded", "stage", stage)
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{"error": "Search took too long during " + stage + ", please try again"})
+	case errors.Is(ctx.Err(), context.Canceled):
+		slog.Info("Client disconnected, AI search cancelled", "stage", stage)
+		c.Abort()
+	case se != nil && se.Status != 0:
+		c.JSON(se.Status, gin.H{"error": se.Message})
+	default:
+		slog.Error("Unexpected AI search error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "AI search failed"})
+	}
 }
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"hackflow-api/internal/guard"
+	"hackflow-api/internal/websearch"
+)
+
+// verifyGrounding reports whether the event's title and date are supported by
+// the cited pages. The title is checked through its verbatim copy, since the
+// title itself may be translated. Without citations nothing can be verified.
+func verifyGrounding(h AIHackathon, cited []websearch.Result) bool {
+	if len(cited) == 0 {
+		return false
+	}
+
+	text := citedText(cited)
+	title := h.SourceTitle
+	if strings.TrimSpace(title) == "" {
+		title = h.Title
+	}
+	return guard.TitleGrounded(title, text) && guard.DateGrounded(h.Date, text)
+}
+
+// citedText joins the titles and contents of the cited pages.
+func citedText(cited []websearch.Result) string {
+	var b strings.Builder
+	for _, res := range cited {
+		b.WriteString(res.Title)
+		b.WriteString(" ")
+		b.WriteString(res.Content)
+		b.WriteString(" ")
+	}
+	return b.String()
+}
+
+// checkOutput drops values the sources do not support and that a page could
+// have planted: a title that is neither on the cited pages nor a translation
+// of the verbatim one into lang is replaced by it, a link, prize pool, fee, team size or
+// organizer absent from the cited pages is cleared, and a LIVE status with a
+// past deadline becomes DEAD.
+func checkOutput(h *AIHackathon, cited []websearch.Result, lang string, today time.Time) {
+	var text strings.Builder
+	urls := make([]string, 0, len(cited))
+	for _, res := range cited {
+		urls = append(urls, res.URL)
+		text.WriteString(res.Content)
+		text.WriteString(" ")
+	}
+
+	if title := guard.GroundTitle(h.Title, h.SourceTitle, citedText(cited), lang); title != h.Title {
+		slog.Debug("Replacing title not found in cited sources", "title", h.Title, "source_title", h.SourceTitle)
+		h.Title = title
+	}
+	if h.Link != nil && *h.Link != "" && !guard.LinkGrounded(*h.Link, text.String(), urls) {
+		slog.Debug("Dropping link not found in cited sources", "title", h.Title, "link", *h.Link)
+		h.Link = nil
+	}
+	if dropped := guard.GroundDetails(&h.EventDetails, &h.Organizer, text.String()); len(dropped) > 0 {
+		slog.Debug("Dropping details not found in cited sources", "title", h.Title, "fields", dropped)
+	}
+
+	if h.Deadline != nil {
+		h.Status = guard.StatusForDeadline(h.Status, *h.Deadline, today)
+	}
+}
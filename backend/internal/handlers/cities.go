@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"hackflow-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CityCount is a city of the reference table with the number of approved
+// hackathons held in it.
+type CityCount struct {
+	Slug   string            `json:"slug"`
+	Region string            `json:"region"`
+	Name   string            `json:"name"`
+	Names  map[string]string `json:"names"`
+	Lat    float64           `json:"lat"`
+	Lon    float64           `json:"lon"`
+	Count  int64             `json:"count"`
+}
+
+// GetCities handles GET /api/cities?region=kz&lang=ru. Cities are sorted by
+// the number of approved hackathons; name is given in lang (by default the
+// region's language).
+func (h *Handler) GetCities(c *gin.Context) {
+	ctx := c.Request.Context()
+	regionCode := strings.ToLower(strings.TrimSpace(c.Query("region")))
+	lang := strings.ToLower(strings.TrimSpace(c.Query("lang")))
+
+	db := h.DB.WithContext(ctx)
+	if regionCode != "" {
+		db = db.Where("region = ?", regionCode)
+	}
+	var cities []models.City
+	if err := db.Order("slug").Find(&cities).Error; err != nil {
+		slog.Error("Failed to fetch cities", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data"})
+		return
+	}
+
+	// Как и фильтр city в GET /api/hackathons: национальный ивент считается
+	// во всех городах своего региона
+	var counts []struct {
+		Slug  string
+		Count int64
+	}
+	if err := h.DB.WithContext(ctx).Raw(`
+		SELECT c.slug, COUNT(DISTINCT h.id) AS count
+		FROM cities c
+		JOIN hackathons h ON h.moderation_status = ? AND h.deleted_at IS NULL
+		JOIN hackathon_cities hc ON hc.hackathon_id = h.id
+		JOIN cities hcity ON hcity.id = hc.city_id
+		WHERE hcity.id = c.id OR (h.national AND hcity.region = c.region)
+		GROUP BY c.slug`, models.ModerationApproved).Scan(&counts).Error; err != nil {
+		slog.Error("Failed to count hackathons per city", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data"})
+		return
+	}
+	bySlug := make(map[string]int64, len(counts))
+	for _, cnt := range counts {
+		bySlug[cnt.Slug] = cnt.Count
+	}
+
+	out := make([]CityCount, 0, len(cities))
+	for _, city := range cities {
+		out = append(out, CityCount{
+			Slug:   city.Slug,
+			Region: city.Region,
+			Name:   h.cityName(city, lang),
+			Names:  city.Names,
+			Lat:    city.Lat,
+			Lon:    city.Lon,
+			Count:  bySlug[city.Slug],
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Count > out[j].Count })
+
+	c.JSON(http.StatusOK, out)
+}
+
+// cityName picks the name in lang, falling back to the language of the
+// city's region.
+func (h *Handler) cityName(city models.City, lang string) string {
+	if name, ok := city.Names[lang]; ok {
+		return name
+	}
+	if h.Regions != nil {
+		if loc, err := h.Regions.Resolve(city.Region, ""); err == nil {
+			if name, ok := city.Names[loc.Lang]; ok {
+				return name
+			}
+		}
+	}
+	return city.Slug
+}
@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"database/sql"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"hackflow-api/internal/models"
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/taxonomy"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -15,37 +19,293 @@ import (
 // Handler contains injected dependencies for the HTTP handlers
 type Handler struct {
 	DB *gorm.DB
+	// Regions resolves city names in filters to the cities table
+	Regions *region.Registry
+	// Tags resolves tag names in filters to the tags table
+	Tags *taxonomy.Taxonomy
 }
 
 // New creates a new Handler with the given database connection
-func New(db *gorm.DB) *Handler {
+func New(db *gorm.DB, regions *region.Registry, tags *taxonomy.Taxonomy) *Handler {
 	return &Handler{
-		DB: db,
+		DB:      db,
+		Regions: regions,
+		Tags:    tags,
 	}
 }
 
-// GetHackathons handles the GET /api/hackathons requests
+// HackathonFilter narrows down the approved hackathons returned by the API.
+type HackathonFilter struct {
+	// Query matches the title or the city.
+	Query string
+	// CitySlug keeps only events held in the city with this slug, including
+	// national events of its region.
+	CitySlug string
+	// Online, if set, keeps only online (or only offline-only) events.
+	Online *bool
+	// Format keeps events that can be attended this way; hybrid events match
+	// both offline and online.
+	Format models.Format
+	// Audience keeps events open to this audience, including events open to
+	// anyone.
+	Audience models.Audience
+	// Age keeps events whose age bounds admit a participant of this age.
+	Age *int
+	// HasPrize, if set, keeps events with (or without) a known prize pool.
+	HasPrize *bool
+	// MinPrize keeps events whose prize pool in PrizeCurrency is at least
+	// this amount.
+	MinPrize      int64
+	PrizeCurrency string
+	// Track keeps events with a track containing this text.
+	Track string
+	// TeamSize keeps events whose team size bounds admit a team this large.
+	TeamSize *int
+	// Organizer keeps events of the organizer with this normalized name.
+	Organizer string
+	// Free, if set, keeps events without (or with) a participation fee; a fee
+	// that is not mentioned counts as free.
+	Free *bool
+	// Language keeps events held in this language (ISO 639-1).
+	Language string
+	// TagSlugs keeps events with any of these tags.
+	TagSlugs []string
+}
+
+// GetHackathons handles the GET /api/hackathons requests. The optional city
+// parameter accepts a slug or any known spelling ("Нур-Султан", "Astana");
+// online=true|false filters by format; format=offline|online|hybrid,
+// audience=school|students|professionals and age=N narrow it further, as do
+// prize=true|false, minPrize=N with currency=KZT, track, teamSize=N,
+// organizer, free=true|false and language=ru. tags=ai-ml,fintech keeps events
+// with any of the listed tags (slugs or names).
 func (h *Handler) GetHackathons(c *gin.Context) {
 	query := strings.TrimSpace(c.Query("q"))
+	filter := HackathonFilter{Query: query}
+	if city := strings.TrimSpace(c.Query("city")); city != "" {
+		filter.CitySlug = h.citySlug(city)
+	}
+	if v := c.Query("online"); v != "" {
+		online, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter 'online' must be true or false"})
+			return
+		}
+		filter.Online = &online
+	}
+	if v := c.Query("format"); v != "" {
+		filter.Format = models.Format(v)
+		if !filter.Format.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter 'format' must be offline, online or hybrid"})
+			return
+		}
+	}
+	if v := c.Query("audience"); v != "" {
+		filter.Audience = models.Audience(v)
+		if !filter.Audience.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter 'audience' must be school, students or professionals"})
+			return
+		}
+	}
+	if v := c.Query("age"); v != "" {
+		age, err := strconv.Atoi(v)
+		if err != nil || age < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter 'age' must be a non-negative integer"})
+			return
+		}
+		filter.Age = &age
+	}
+	if !parseDetailsFilter(c, &filter) {
+		return
+	}
+	if v := strings.TrimSpace(c.Query("tags")); v != "" {
+		filter.TagSlugs = h.tagSlugs(strings.Split(v, ","))
+	}
 
-	var hackathons []models.Hackathon
-
-	if query == "" {
-		if err := h.DB.Find(&hackathons).Error; err != nil {
+	hackathons, err := findHackathons(h.DB.WithContext(c.Request.Context()), filter)
+	if err != nil {
+		if query == "" {
 			slog.Error("Failed to fetch hackathons from database", "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data"})
-			return
-		}
-	} else {
-		searchPattern := "%" + query + "%"
-		slog.Debug("Searching hackathons", "query", query)
-		if err := h.DB.Where("title ILIKE ? OR city ILIKE ?", searchPattern, searchPattern).Find(&hackathons).Error; err != nil {
+		} else {
 			slog.Error("Failed to search hackathons", "error", err, "query", query)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search data"})
-			return
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, hackathons)
+}
+
+// parseDetailsFilter reads the prize, track, team, organizer, fee and language
+// parameters into filter; on a malformed value it responds 400 and returns
+// false.
+func parseDetailsFilter(c *gin.Context, filter *HackathonFilter) bool {
+	if v := c.Query("prize"); v != "" {
+		hasPrize, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter 'prize' must be true or false"})
+			return false
+		}
+		filter.HasPrize = &hasPrize
+	}
+	if v := c.Query("minPrize"); v != "" {
+		minPrize, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || minPrize < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter 'minPrize' must be a non-negative integer"})
+			return false
+		}
+		filter.PrizeCurrency = models.ParseCurrency(c.Query("currency"))
+		if filter.PrizeCurrency == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter 'minPrize' requires a currency such as KZT or USD"})
+			return false
+		}
+		filter.MinPrize = minPrize
+	}
+	if v := c.Query("teamSize"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter 'teamSize' must be a positive integer"})
+			return false
+		}
+		filter.TeamSize = &size
+	}
+	if v := c.Query("free"); v != "" {
+		free, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter 'free' must be true or false"})
+			return false
+		}
+		filter.Free = &free
+	}
+	filter.Track = strings.TrimSpace(c.Query("track"))
+	filter.Organizer = models.NormalizeTitle(c.Query("organizer"))
+	filter.Language = strings.ToLower(strings.TrimSpace(c.Query("language")))
+	return true
+}
+
+// tagSlugs resolves tag filter values; like citySlug, unknown tags keep the
+// raw value so they match nothing.
+func (h *Handler) tagSlugs(names []string) []string {
+	var slugs []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if tag, ok := h.Tags.Find(name); ok {
+			name = tag.Slug
+		}
+		slugs = append(slugs, name)
+	}
+	return slugs
+}
+
+// citySlug resolves a city filter value; unknown cities keep the raw value so
+// the filter matches nothing instead of being ignored.
+func (h *Handler) citySlug(city string) string {
+	if h.Regions != nil {
+		if c, ok := h.Regions.FindCity(city); ok {
+			return c.Slug
 		}
 	}
+	return city
+}
+
+// findHackathons returns the approved hackathons matching filter, with
+// statuses refreshed against the current time.
+func findHackathons(db *gorm.DB, filter HackathonFilter) ([]models.Hackathon, error) {
+	var hackathons []models.Hackathon
 
+	db = db.Preload("Cities").Preload("Organizer").Preload("Tags").Where("moderation_status = ?", models.ModerationApproved)
+
+	if filter.CitySlug != "" {
+		// Национальные ивенты показываем во всех городах их региона
+		db = db.Where(`hackathons.id IN (
+			SELECT hc.hackathon_id FROM hackathon_cities hc JOIN cities c ON c.id = hc.city_id
+			WHERE c.slug = @slug OR (hackathons.national AND c.region = (SELECT region FROM cities WHERE slug = @slug))
+		)`, sql.Named("slug", filter.CitySlug))
+	}
+	if filter.Online != nil {
+		db = db.Where("online = ?", *filter.Online)
+	}
+	switch filter.Format {
+	case models.FormatOffline, models.FormatOnline:
+		db = db.Where("format IN ?", []models.Format{filter.Format, models.FormatHybrid})
+	case models.FormatHybrid:
+		db = db.Where("format = ?", filter.Format)
+	}
+	if filter.Audience != "" {
+		// audiences хранится как jsonb: null или [] — ивент для всех
+		db = db.Where("jsonb_typeof(audiences) IS DISTINCT FROM 'array' OR audiences = '[]'::jsonb OR audiences @> ?::jsonb",
+			`["`+string(filter.Audience)+`"]`)
+	}
+	if filter.Age != nil {
+		db = db.Where("(min_age IS NULL OR min_age <= @age) AND (max_age IS NULL OR max_age >= @age)", sql.Named("age", *filter.Age))
+	}
+	db = whereDetails(db, filter)
+	if len(filter.TagSlugs) > 0 {
+		db = db.Where(`hackathons.id IN (
+			SELECT ht.hackathon_id FROM hackathon_tags ht JOIN tags t ON t.id = ht.tag_id
+			WHERE t.slug IN ?
+		)`, filter.TagSlugs)
+	}
+
+	if filter.Query != "" {
+		searchPattern := "%" + filter.Query + "%"
+		slog.Debug("Searching hackathons", "query", filter.Query)
+		db = db.Where("title ILIKE ? OR city ILIKE ?", searchPattern, searchPattern)
+	}
+
+	if err := db.Find(&hackathons).Error; err != nil {
+		return nil, err
+	}
+
+	refreshStatuses(hackathons)
+	return hackathons, nil
+}
+
+// whereDetails applies the prize, track, team, organizer, fee and language
+// parts of filter.
+func whereDetails(db *gorm.DB, filter HackathonFilter) *gorm.DB {
+	if filter.HasPrize != nil {
+		if *filter.HasPrize {
+			db = db.Where("prize_pool IS NOT NULL")
+		} else {
+			db = db.Where("prize_pool IS NULL")
+		}
+	}
+	if filter.PrizeCurrency != "" {
+		db = db.Where("prize_currency = ? AND prize_pool >= ?", filter.PrizeCurrency, filter.MinPrize)
+	}
+	if filter.Track != "" {
+		// tracks хранится как jsonb: null для записей без треков
+		db = db.Where(`EXISTS (
+			SELECT 1 FROM jsonb_array_elements_text(CASE WHEN jsonb_typeof(tracks) = 'array' THEN tracks ELSE '[]'::jsonb END) t
+			WHERE t ILIKE ?
+		)`, "%"+filter.Track+"%")
+	}
+	if filter.TeamSize != nil {
+		db = db.Where("(team_size_min IS NULL OR team_size_min <= @size) AND (team_size_max IS NULL OR team_size_max >= @size)", sql.Named("size", *filter.TeamSize))
+	}
+	if filter.Organizer != "" {
+		db = db.Where("organizer_id IN (SELECT id FROM organizers WHERE key = ?)", filter.Organizer)
+	}
+	if filter.Free != nil {
+		if *filter.Free {
+			db = db.Where("fee IS NULL OR fee = 0")
+		} else {
+			db = db.Where("fee > 0")
+		}
+	}
+	if filter.Language != "" {
+		db = db.Where("language = ?", filter.Language)
+	}
+	return db
+}
+
+// refreshStatuses marks events whose deadline has passed as DEAD.
+func refreshStatuses(hackathons []models.Hackathon) {
 	// Динамическая проверка статуса для старых записей
 	now := time.Now()
 	for i := range hackathons {
@@ -56,6 +316,4 @@ func (h *Handler) GetHackathons(c *gin.Context) {
 			hackathons[i].Status = "DEAD"
 		}
 	}
-
-	c.JSON(http.StatusOK, hackathons)
 }
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"hackflow-api/internal/llmusage"
+	"hackflow-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// webSearchBudgetExceeded is the X-Web-Search value sent when the daily LLM
+// token budget for search is used up and only database results are returned.
+const webSearchBudgetExceeded = "budget-exceeded"
+
+// Result sources in hybrid search responses.
+const (
+	SourceDB  = "db"
+	SourceWeb = "web"
+)
+
+// SearchResult is a hackathon from either the database or the web agent,
+// tagged with where it came from.
+type SearchResult struct {
+	ID        uint              `json:"id,omitempty"`
+	Title     string            `json:"title"`
+	Date      string            `json:"date"`
+	Deadline  *string           `json:"deadline"`
+	Format    string            `json:"format"`
+	City      string            `json:"city"`
+	CitySlugs []string          `json:"citySlugs,omitempty"`
+	National  bool              `json:"national"`
+	Online    bool              `json:"online"`
+	MinAge    *int              `json:"minAge"`
+	MaxAge    *int              `json:"maxAge"`
+	Audiences []models.Audience `json:"audiences"`
+	models.EventDetails
+	Organizer    string   `json:"organizer,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Link         *string  `json:"link"`
+	Status       string   `json:"status"`
+	ImageURL     string   `json:"imageUrl,omitempty"`
+	ThumbnailURL string   `json:"thumbnailUrl,omitempty"`
+	Source       string   `json:"source"`
+	// Sources and Verified are only set for web results (see AIHackathon).
+	Sources  []Source `json:"sources,omitempty"`
+	Verified *bool    `json:"verified,omitempty"`
+}
+
+func resultFromHackathon(h models.Hackathon) SearchResult {
+	r := SearchResult{
+		ID:           h.ID,
+		Title:        h.Title,
+		Date:         h.Date,
+		Format:       string(h.Format),
+		City:         h.City,
+		National:     h.National,
+		Online:       h.Online,
+		MinAge:       h.MinAge,
+		MaxAge:       h.MaxAge,
+		Audiences:    h.Audiences,
+		EventDetails: h.EventDetails,
+		Status:       h.Status,
+		ImageURL:     h.ImageURL,
+		ThumbnailURL: h.ThumbnailURL,
+		Source:       SourceDB,
+	}
+	if h.Deadline != nil {
+		d := h.Deadline.Format("2006-01-02")
+		r.Deadline = &d
+	}
+	if h.Link != "" {
+		link := h.Link
+		r.Link = &link
+	}
+	if h.Organizer != nil {
+		r.Organizer = h.Organizer.Name
+	}
+	for _, tag := range h.Tags {
+		r.Tags = append(r.Tags, tag.Slug)
+	}
+	for _, city := range h.Cities {
+		r.CitySlugs = append(r.CitySlugs, city.Slug)
+	}
+	return r
+}
+
+func resultFromAI(a AIHackathon) SearchResult {
+	return SearchResult{
+		Title:        a.Title,
+		Date:         a.Date,
+		Deadline:     a.Deadline,
+		Format:       a.Format,
+		City:         a.City,
+		CitySlugs:    a.CitySlugs,
+		National:     a.National,
+		Online:       models.Format(a.Format).Online(),
+		MinAge:       a.MinAge,
+		MaxAge:       a.MaxAge,
+		Audiences:    a.Audiences,
+		EventDetails: a.EventDetails,
+		Organizer:    a.Organizer,
+		Tags:         a.Tags,
+		Link:         a.Link,
+		Status:       a.Status,
+		Source:       SourceWeb,
+		Sources:      a.Sources,
+		Verified:     &a.Verified,
+	}
+}
+
+// HybridSearchHandler merges database results with the AI web agent.
+type HybridSearchHandler struct {
+	DB    *gorm.DB
+	Agent *SearchAIHandler
+	// MinLocalResults is the number of database hits below which the web
+	// agent is consulted.
+	MinLocalResults int
+}
+
+// NewHybridSearchHandler creates a HybridSearchHandler.
+func NewHybridSearchHandler(db *gorm.DB, agent *SearchAIHandler, minLocalResults int) *HybridSearchHandler {
+	return &HybridSearchHandler{
+		DB:              db,
+		Agent:           agent,
+		MinLocalResults: minLocalResults,
+	}
+}
+
+// HybridSearch handles GET /api/search/hybrid. It answers from the database
+// and runs the web agent only when local results are thin. Web results that
+// match a stored hackathon (by normalized title) are replaced by the stored
+// record. The X-Web-Search header reports whether the agent was skipped, used,
+// failed or budget-exceeded; in the last two cases the database results are
+// still returned. Web agent
+// answers are cached like GET /api/search, with X-Cache and refresh=true.
+func (h *HybridSearchHandler) HybridSearch(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'q' is required"})
+		return
+	}
+
+	loc, ok := h.Agent.resolveLocale(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	db := h.DB.WithContext(ctx)
+
+	local, err := findHackathons(db, HackathonFilter{Query: query})
+	if err != nil {
+		slog.Error("Failed to search hackathons", "error", err, "query", query)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search data"})
+		return
+	}
+
+	merged := make([]SearchResult, 0, len(local))
+	seen := make(map[string]bool, len(local))
+	for _, hackathon := range local {
+		merged = append(merged, resultFromHackathon(hackathon))
+		seen[models.NormalizeTitle(hackathon.Title)] = true
+	}
+
+	if len(local) >= h.MinLocalResults {
+		slog.Debug("Hybrid search answered from database", "query", query, "results", len(local))
+		c.Header("X-Web-Search", "skipped")
+		c.JSON(http.StatusOK, merged)
+		return
+	}
+
+	webResults, cacheStatus, _, err := h.Agent.CachedSearch(ctx, query, loc, c.Query("refresh") == "true")
+	if errors.Is(err, llmusage.ErrBudgetExceeded) {
+		slog.Info("AI search budget exceeded, returning database results", "query", query)
+		c.Header("X-Web-Search", webSearchBudgetExceeded)
+		c.JSON(http.StatusOK, merged)
+		return
+	}
+	if err != nil {
+		// Клиент ушел, или дедлайн истек, а из БД отдать нечего
+		if errors.Is(ctx.Err(), context.Canceled) || (ctx.Err() != nil && len(merged) == 0) {
+			respondSearchError(c, ctx, err)
+			return
+		}
+		slog.Warn("Web agent failed in hybrid search, returning database results", "error", err, "query", query)
+		c.Header("X-Web-Search", "failed")
+		c.JSON(http.StatusOK, merged)
+		return
+	}
+
+	merged = h.mergeWeb(db, merged, seen, webResults)
+	if cacheStatus != "" {
+		c.Header("X-Cache", cacheStatus)
+	}
+
+	slog.Info("Hybrid search completed", "query", query, "db_results", len(local), "total_results", len(merged))
+	c.Header("X-Web-Search", "used")
+	c.JSON(http.StatusOK, merged)
+}
+
+// mergeWeb appends web results that are not already present. Web results
+// matching an approved hackathon stored in the database (but not in the local
+// hits) are replaced by the stored record; those matching a rejected one are
+// dropped.
+func (h *HybridSearchHandler) mergeWeb(db *gorm.DB, merged []SearchResult, seen map[string]bool, webResults []AIHackathon) []SearchResult {
+	keys := make([]string, 0, len(webResults))
+	for _, w := range webResults {
+		if key := models.NormalizeTitle(w.Title); key != "" && !seen[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	stored := make(map[string]models.Hackathon)
+	if len(keys) > 0 {
+		var known []models.Hackathon
+		if err := db.Preload("Cities").Preload("Organizer").Preload("Tags").Where("dedup_key IN ?", keys).Find(&known).Error; err != nil {
+			slog.Warn("Failed to dedup web results against database", "error", err)
+		}
+		refreshStatuses(known)
+		for _, k := range known {
+			stored[k.DedupKey] = k
+		}
+	}
+
+	for _, w := range webResults {
+		key := models.NormalizeTitle(w.Title)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		k, ok := stored[key]
+		switch {
+		case !ok || k.ModerationStatus == models.ModerationPending:
+			merged = append(merged, resultFromAI(w))
+		case k.ModerationStatus == models.ModerationApproved:
+			merged = append(merged, resultFromHackathon(k))
+		default:
+			// Отклонено модератором — не показываем и из веба
+			slog.Debug("Dropping web result rejected by moderation", "title", w.Title)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"hackflow-api/internal/llmusage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageHandler exposes LLM token accounting to admins and monitoring.
+type UsageHandler struct {
+	Usage *llmusage.Tracker
+}
+
+// NewUsageHandler creates a UsageHandler.
+func NewUsageHandler(usage *llmusage.Tracker) *UsageHandler {
+	return &UsageHandler{
+		Usage: usage,
+	}
+}
+
+// CallerUsage is today's token usage of one caller against its budget.
+type CallerUsage struct {
+	Caller string `json:"caller"`
+	Used   int64  `json:"used"`
+	// Budget is the daily token budget; 0 means unlimited
+	Budget   int64 `json:"budget"`
+	Exceeded bool  `json:"exceeded"`
+}
+
+// GetUsage handles GET /api/admin/llm-usage?days=7. It returns today's usage
+// per caller and the stored per-day, per-model history.
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 1 || days > 90 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter 'days' must be between 1 and 90"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	callers := []string{llmusage.CallerSearch, llmusage.CallerScraper, llmusage.CallerClassifier}
+	today := make([]CallerUsage, 0, len(callers))
+	for _, caller := range callers {
+		used, err := h.Usage.Today(ctx, caller)
+		if err != nil {
+			slog.Error("Failed to read today's LLM usage", "error", err, "caller", caller)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data"})
+			return
+		}
+		budget := h.Usage.Budget(caller)
+		today = append(today, CallerUsage{
+			Caller:   caller,
+			Used:     used,
+			Budget:   budget,
+			Exceeded: budget > 0 && used >= budget,
+		})
+	}
+
+	history, err := h.Usage.History(ctx, days)
+	if err != nil {
+		slog.Error("Failed to read LLM usage history", "error", err, "days", days)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"today": today, "history": history})
+}
+
+// Metrics handles GET /metrics in the Prometheus text format. Counters are
+// per process; the database totals are served by GetUsage.
+func (h *UsageHandler) Metrics(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+	h.Usage.WriteMetrics(c.Writer)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"hackflow-api/internal/database"
+	"hackflow-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// validateAIHackathon checks that an AI search result is complete enough to be
+// reviewed by a moderator.
+func validateAIHackathon(a AIHackathon) error {
+	title := strings.TrimSpace(a.Title)
+	if title == "" || title == "null" || models.NormalizeTitle(title) == "" {
+		return errors.New("empty title")
+	}
+	if strings.TrimSpace(a.Date) == "" {
+		return errors.New("empty date")
+	}
+	if !a.Verified {
+		return errors.New("title or date not found in cited sources")
+	}
+	if a.Status != "LIVE" {
+		return fmt.Errorf("status %q is not LIVE", a.Status)
+	}
+	if a.Deadline != nil && *a.Deadline != "" {
+		if _, err := time.Parse("2006-01-02", *a.Deadline); err != nil {
+			return fmt.Errorf("invalid deadline %q", *a.Deadline)
+		}
+	}
+	if a.Link != nil && *a.Link != "" {
+		u, err := url.Parse(*a.Link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid link %q", *a.Link)
+		}
+	}
+	return nil
+}
+
+// hackathonFromAI converts a validated AI result into a pending hackathon.
+func hackathonFromAI(a AIHackathon) *models.Hackathon {
+	h := &models.Hackathon{
+		Title:            strings.TrimSpace(a.Title),
+		Date:             a.Date,
+		Format:           models.ParseFormat(a.Format),
+		MinAge:           a.MinAge,
+		MaxAge:           a.MaxAge,
+		Audiences:        a.Audiences,
+		City:             a.City,
+		National:         a.National,
+		EventDetails:     a.EventDetails,
+		Status:           a.Status,
+		ModerationStatus: models.ModerationPending,
+		SourceURLs:       a.SourceURLs,
+		PromptVersion:    a.PromptVersion,
+	}
+	if a.Link != nil {
+		h.Link = *a.Link
+	}
+	if a.Deadline != nil && *a.Deadline != "" {
+		if d, err := time.Parse("2006-01-02", *a.Deadline); err == nil {
+			h.Deadline = &d
+		}
+	}
+	return h
+}
+
+// savePending stores valid AI search discoveries in the moderation queue.
+// Events already known (by dedup key) are left untouched, whatever their
+// moderation status. Failures are logged and never fail the search itself.
+func savePending(ctx context.Context, db *gorm.DB, found []AIHackathon) {
+	if db == nil {
+		return
+	}
+
+	saved := 0
+	for _, a := range found {
+		if err := validateAIHackathon(a); err != nil {
+			slog.Debug("AI search result not queued for moderation", "title", a.Title, "reason", err)
+			continue
+		}
+
+		h := hackathonFromAI(a)
+		res := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(h)
+		if res.Error != nil {
+			slog.Warn("Failed to queue AI search result for moderation", "title", a.Title, "error", res.Error)
+			continue
+		}
+		if res.RowsAffected == 0 {
+			continue
+		}
+		saved++
+
+		if err := database.LinkCities(db.WithContext(ctx), h, a.CitySlugs); err != nil {
+			slog.Warn("Failed to link AI search result to cities", "title", a.Title, "error", err)
+		}
+		if err := database.LinkTags(db.WithContext(ctx), h, a.Tags); err != nil {
+			slog.Warn("Failed to tag AI search result", "title", a.Title, "error", err)
+		}
+		if err := database.LinkOrganizer(db.WithContext(ctx), h, a.Organizer); err != nil {
+			slog.Warn("Failed to save organizer of AI search result", "title", a.Title, "organizer", a.Organizer, "error", err)
+		}
+	}
+
+	if saved > 0 {
+		slog.Info("AI search discoveries queued for moderation", "count", saved)
+	}
+}
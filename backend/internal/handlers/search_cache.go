@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"hackflow-api/internal/cache"
+	"hackflow-api/internal/models"
+	"hackflow-api/internal/prompts"
+	"hackflow-api/internal/region"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Values of the X-Cache response header.
+const (
+	CacheHit     = "HIT"
+	CacheMiss    = "MISS"
+	CacheRefresh = "REFRESH"
+)
+
+// SearchCache stores AI search responses in an in-memory LRU and, optionally,
+// in Postgres so that identical queries don't hit the web search provider and
+// Gemini again. Keys include the current date because statuses in the answer
+// are relative to it.
+type SearchCache struct {
+	mem *cache.LRU[string, cachedSearch]
+	// db is nil when persistence is disabled
+	db  *gorm.DB
+	ttl time.Duration
+}
+
+type cachedSearch struct {
+	Hackathons []AIHackathon
+	ExpiresAt  time.Time
+}
+
+// NewSearchCache creates a cache for size responses kept for ttl. It returns
+// nil (caching disabled) when size or ttl is not positive.
+func NewSearchCache(db *gorm.DB, size int, ttl time.Duration, persist bool) *SearchCache {
+	if size <= 0 || ttl <= 0 {
+		return nil
+	}
+
+	sc := &SearchCache{
+		mem: cache.NewLRU[string, cachedSearch](size, ttl),
+		ttl: ttl,
+	}
+	if persist {
+		sc.db = db
+	}
+	return sc
+}
+
+// searchCacheKey normalizes the query the same way hackathon titles are
+// deduplicated, so "Хакатон Алматы" and "хакатон  алматы!" share an entry.
+// Different regions and output languages never share entries, and a new
+// prompt version invalidates answers produced by the old one.
+func searchCacheKey(query string, loc region.Locale) string {
+	version := prompts.Versions()[prompts.SearchTemplate]
+	return fmt.Sprintf("%s|%s|%s|%s", time.Now().Format("2006-01-02"), version, loc.Key(), models.NormalizeTitle(query))
+}
+
+// Get returns a fresh cached response for key.
+func (sc *SearchCache) Get(ctx context.Context, key string) (cachedSearch, bool) {
+	if entry, ok := sc.mem.Get(key); ok {
+		return entry, true
+	}
+	if sc.db == nil {
+		return cachedSearch{}, false
+	}
+
+	var row models.SearchCacheEntry
+	err := sc.db.WithContext(ctx).
+		Where("key = ? AND expires_at > ?", key, time.Now()).
+		Limit(1).Find(&row).Error
+	if err != nil {
+		slog.Warn("Failed to read search cache", "error", err, "key", key)
+		return cachedSearch{}, false
+	}
+	if row.Key == "" {
+		return cachedSearch{}, false
+	}
+
+	entry := cachedSearch{ExpiresAt: row.ExpiresAt}
+	if err := json.Unmarshal(row.Payload, &entry.Hackathons); err != nil {
+		slog.Warn("Discarding corrupted search cache entry", "error", err, "key", key)
+		return cachedSearch{}, false
+	}
+	sc.mem.SetWithTTL(key, entry, time.Until(row.ExpiresAt))
+	return entry, true
+}
+
+// Set stores a response under key. Persistence errors are logged only: the
+// in-memory entry is enough to serve the next request.
+func (sc *SearchCache) Set(ctx context.Context, key, query string, hackathons []AIHackathon) cachedSearch {
+	entry := cachedSearch{Hackathons: hackathons, ExpiresAt: time.Now().Add(sc.ttl)}
+	sc.mem.Set(key, entry)
+	if sc.db == nil {
+		return entry
+	}
+
+	payload, err := json.Marshal(hackathons)
+	if err != nil {
+		slog.Warn("Failed to encode search cache entry", "error", err, "key", key)
+		return entry
+	}
+
+	db := sc.db.WithContext(ctx)
+	row := models.SearchCacheEntry{Key: key, Query: query, Payload: payload, ExpiresAt: entry.ExpiresAt}
+	if err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&row).Error; err != nil {
+		slog.Warn("Failed to persist search cache entry", "error", err, "key", key)
+	}
+	if err := db.Where("expires_at <= ?", time.Now()).Delete(&models.SearchCacheEntry{}).Error; err != nil {
+		slog.Warn("Failed to purge expired search cache entries", "error", err)
+	}
+	return entry
+}
+
+// CachedSearch answers from the cache when possible and falls back to Search.
+// refresh skips the lookup but still stores the new response. The returned
+// status is one of CacheHit, CacheMiss or CacheRefresh, or empty when caching
+// is disabled.
+func (h *SearchAIHandler) CachedSearch(ctx context.Context, query string, loc region.Locale, refresh bool) ([]AIHackathon, string, time.Time, error) {
+	if h.Cache == nil {
+		hackathons, err := h.Search(ctx, query, loc)
+		return hackathons, "", time.Time{}, err
+	}
+
+	key := searchCacheKey(query, loc)
+	status := CacheRefresh
+	if !refresh {
+		if entry, ok := h.Cache.Get(ctx, key); ok {
+			slog.Debug("AI search served from cache", "query", query, "key", key)
+			return entry.Hackathons, CacheHit, entry.ExpiresAt, nil
+		}
+		status = CacheMiss
+	}
+
+	hackathons, err := h.Search(ctx, query, loc)
+	if err != nil {
+		return nil, status, time.Time{}, err
+	}
+	entry := h.Cache.Set(ctx, key, query, hackathons)
+	return hackathons, status, entry.ExpiresAt, nil
+}
+
+// setCacheHeaders reports the cache status and lets clients and proxies keep
+// the response until the server-side entry expires.
+func setCacheHeaders(c *gin.Context, status string, expiresAt time.Time) {
+	if status == "" {
+		return
+	}
+	c.Header("X-Cache", status)
+
+	maxAge := int(math.Max(0, time.Until(expiresAt).Seconds()))
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/websearch"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+)
+
+// emptySearcher finds nothing, so Search answers without calling Gemini.
+type emptySearcher struct{ calls int }
+
+func (s *emptySearcher) Search(context.Context, string, int) ([]websearch.Result, error) {
+	s.calls++
+	return nil, nil
+}
+
+func (s *emptySearcher) Name() string { return "empty" }
+
+func testLocales(t *testing.T) (*region.Registry, region.Locale) {
+	t.Helper()
+	regions, err := region.Load("", "kz", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, err := regions.Resolve("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return regions, loc
+}
+
+func newCachedHandler(t *testing.T) (*SearchAIHandler, *emptySearcher) {
+	t.Helper()
+	regions, _ := testLocales(t)
+	// the client is never called: the searcher returns no results
+	gemini, err := genai.NewClient(context.Background(), option.WithAPIKey("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { gemini.Close() })
+
+	searcher := &emptySearcher{}
+	return &SearchAIHandler{
+		Gemini:   gemini,
+		Searcher: searcher,
+		Cache:    NewSearchCache(nil, 10, time.Hour, false),
+		Regions:  regions,
+	}, searcher
+}
+
+func TestSearchCacheKey(t *testing.T) {
+	regions, loc := testLocales(t)
+
+	key := searchCacheKey("Хакатон Алматы", loc)
+	if got := searchCacheKey("  хакатон   АЛМАТЫ! ", loc); got != key {
+		t.Errorf("normalized query key = %q, want %q", got, key)
+	}
+	if !strings.HasPrefix(key, time.Now().Format("2006-01-02")+"|") {
+		t.Errorf("key %q does not start with today's date", key)
+	}
+	if got := searchCacheKey("Хакатон Астана", loc); got == key {
+		t.Errorf("different queries share key %q", key)
+	}
+
+	en, err := regions.Resolve("", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := searchCacheKey("Хакатон Алматы", en); got == key {
+		t.Errorf("different output languages share key %q", key)
+	}
+}
+
+func TestSearchAICacheHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h, searcher := newCachedHandler(t)
+	r := gin.New()
+	r.GET("/api/search", h.SearchAI)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", url, w.Code, w.Body)
+		}
+		return w
+	}
+
+	tests := []struct {
+		url      string
+		want     string
+		searches int
+	}{
+		{"/api/search?q=Хакатон+Алматы", CacheMiss, 1},
+		{"/api/search?q=хакатон++алматы!", CacheHit, 1},
+		{"/api/search?q=Хакатон+Алматы&refresh=true", CacheRefresh, 2},
+		{"/api/search?q=Хакатон+Алматы", CacheHit, 2},
+		{"/api/search?q=Хакатон+Алматы&refresh=false", CacheHit, 2},
+	}
+	for _, tt := range tests {
+		w := get(tt.url)
+		if got := w.Header().Get("X-Cache"); got != tt.want {
+			t.Errorf("GET %s: X-Cache = %q, want %q", tt.url, got, tt.want)
+		}
+		if searcher.calls != tt.searches {
+			t.Errorf("GET %s: %d web searches so far, want %d", tt.url, searcher.calls, tt.searches)
+		}
+
+		cc := w.Header().Get("Cache-Control")
+		maxAge, err := strconv.Atoi(strings.TrimPrefix(cc, "public, max-age="))
+		if err != nil || maxAge <= 3500 || maxAge > 3600 {
+			t.Errorf("GET %s: Cache-Control = %q, want public with max-age close to an hour", tt.url, cc)
+		}
+	}
+}
+
+func TestSearchAIWithoutCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h, _ := newCachedHandler(t)
+	h.Cache = nil
+	r := gin.New()
+	r.GET("/api/search", h.SearchAI)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/search?q=hackathon", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET = %d: %s", w.Code, w.Body)
+	}
+	if w.Header().Get("X-Cache") != "" || w.Header().Get("Cache-Control") != "" {
+		t.Errorf("cache headers without a cache: X-Cache %q, Cache-Control %q", w.Header().Get("X-Cache"), w.Header().Get("Cache-Control"))
+	}
+}
+
+func TestSetCacheHeadersExpired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	setCacheHeaders(c, CacheHit, time.Now().Add(-time.Minute))
+	if got := w.Header().Get("Cache-Control"); got != "public, max-age=0" {
+		t.Errorf("Cache-Control for an expired entry = %q, want max-age=0", got)
+	}
+}
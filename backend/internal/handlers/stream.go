@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"hackflow-api/internal/llmusage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
+)
+
+// Server-Sent Event names emitted by SearchAIStream.
+const (
+	eventStatus    = "status"
+	eventHackathon = "hackathon"
+	eventDone      = "done"
+	eventError     = "error"
+)
+
+// SearchAIStream handles GET /api/search/stream. It runs the same agent as
+// SearchAI but reports progress and every hackathon as soon as Gemini has
+// produced it, so the UI can render incrementally:
+//
+//	event: status     {"stage":"searching"}
+//	event: status     {"stage":"found","pages":5}
+//	event: status     {"stage":"analyzing"}
+//	event: hackathon  {...}            (one per event)
+//	event: done       {"count":3}
+//	event: error      {"error":"...","status":504}
+//
+// When the daily LLM token budget for search is used up, the stream reports
+// {"stage":"budget-exceeded"} and sends approved database hackathons instead.
+func (h *SearchAIHandler) SearchAIStream(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'q' is required"})
+		return
+	}
+
+	loc, ok := h.resolveLocale(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Отключаем буферизацию в nginx, иначе события придут пачкой в конце
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	send := func(event string, data any) {
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+	}
+
+	slog.Info("Starting streaming Web-Browsing RAG Search", "query", query, "locale", loc.Key())
+	if err := h.Usage.Allow(ctx, llmusage.CallerSearch); err != nil {
+		h.streamFromDB(ctx, send, query)
+		return
+	}
+	send(eventStatus, gin.H{"stage": "searching"})
+
+	results, err := h.searchWeb(ctx, query, loc)
+	if err != nil {
+		sendStreamError(send, ctx, err)
+		return
+	}
+	send(eventStatus, gin.H{"stage": "found", "pages": len(results)})
+	if len(results) == 0 {
+		send(eventDone, gin.H{"count": 0})
+		return
+	}
+
+	prompt, err := buildSearchPrompt(query, results, loc, h.Tags.Slugs())
+	if err != nil {
+		sendStreamError(send, ctx, err)
+		return
+	}
+
+	model := h.newSearchModel()
+
+	send(eventStatus, gin.H{"stage": "analyzing"})
+
+	var (
+		splitter   jsonArraySplitter
+		hackathons []AIHackathon
+		usage      *genai.UsageMetadata
+	)
+	// Метаданные использования приходят с последними чанками; учитываем
+	// токены и при ошибке посреди потока
+	defer func() {
+		h.Usage.Record(ctx, llmusage.CallerSearch, searchModel, llmusage.FromMetadata(usage))
+	}()
+
+	iter := model.GenerateContentStream(ctx, genai.Text(prompt.Text))
+	for {
+		resp, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err == nil && resp.UsageMetadata != nil {
+			usage = resp.UsageMetadata
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("Failed to stream content via Gemini", "error", err)
+				err = &searchError{Status: http.StatusInternalServerError, Message: "Failed to process search results via AI", Stage: "AI analysis", Err: err}
+			}
+			sendStreamError(send, ctx, err)
+			return
+		}
+
+		for _, cand := range resp.Candidates {
+			if cand.Content == nil {
+				continue
+			}
+			for _, part := range cand.Content.Parts {
+				for _, raw := range splitter.Feed(fmt.Sprintf("%v", part)) {
+					var hackathon AIHackathon
+					if err := json.Unmarshal(raw, &hackathon); err != nil {
+						slog.Warn("Skipping malformed streamed hackathon", "error", err, "raw_json", string(raw))
+						continue
+					}
+					attachSource(&hackathon, results, loc.Lang)
+					normalizeHackathon(&hackathon, loc, h.Tags)
+					hackathon.PromptVersion = prompt.Version
+					hackathons = append(hackathons, hackathon)
+					send(eventHackathon, hackathon)
+				}
+			}
+		}
+	}
+
+	savePending(ctx, h.DB, hackathons)
+
+	slog.Info("Streaming AI Search completed successfully", "results_count", len(hackathons))
+	send(eventDone, gin.H{"count": len(hackathons)})
+}
+
+// streamFromDB sends approved database hackathons when the search budget is
+// used up.
+func (h *SearchAIHandler) streamFromDB(ctx context.Context, send func(string, any), query string) {
+	send(eventStatus, gin.H{"stage": webSearchBudgetExceeded})
+
+	local, err := findHackathons(h.DB.WithContext(ctx), HackathonFilter{Query: query})
+	if err != nil {
+		slog.Error("Failed to search hackathons", "error", err, "query", query)
+		send(eventError, gin.H{"error": "Failed to search data", "status": http.StatusInternalServerError})
+		return
+	}
+	for _, hackathon := range local {
+		send(eventHackathon, resultFromHackathon(hackathon))
+	}
+
+	slog.Info("AI search budget exceeded, streamed database results", "query", query, "results", len(local))
+	send(eventDone, gin.H{"count": len(local)})
+}
+
+// sendStreamError reports an error as an SSE event; headers are already sent,
+// so the HTTP status travels in the payload instead.
+func sendStreamError(send func(string, any), ctx context.Context, err error) {
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		slog.Info("Client disconnected, streaming AI search cancelled")
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		slog.Warn("Streaming AI search deadline exceeded")
+		send(eventError, gin.H{"error": "Search took too long, please try again", "status": http.StatusGatewayTimeout})
+	default:
+		var se *searchError
+		if errors.As(err, &se) && se.Status != 0 {
+			send(eventError, gin.H{"error": se.Message, "status": se.Status})
+			return
+		}
+		slog.Error("Unexpected streaming AI search error", "error", err)
+		send(eventError, gin.H{"error": "AI search failed", "status": http.StatusInternalServerError})
+	}
+}
+
+// jsonArraySplitter extracts complete top-level objects from a JSON array that
+// arrives in arbitrary chunks. Anything before the opening '[' (e.g. a
+// Markdown fence) is ignored.
+type jsonArraySplitter struct {
+	buf      []byte
+	pos      int
+	started  bool
+	depth    int
+	inString bool
+	escaped  bool
+	objStart int
+}
+
+// Feed appends a chunk and returns the objects completed by it.
+func (s *jsonArraySplitter) Feed(chunk string) []json.RawMessage {
+	s.buf = append(s.buf, chunk...)
+
+	var out []json.RawMessage
+	for ; s.pos < len(s.buf); s.pos++ {
+		ch := s.buf[s.pos]
+
+		if !s.started {
+			if ch == '[' {
+				s.started = true
+				s.depth = 1
+			}
+			continue
+		}
+
+		if s.inString {
+			switch {
+			case s.escaped:
+				s.escaped = false
+			case ch == '\\':
+				s.escaped = true
+			case ch == '"':
+				s.inString = false
+			}
+			continue
+		}
+
+		switch ch {
+		case '"':
+			s.inString = true
+		case '{', '[':
+			if s.depth == 1 && ch == '{' {
+				s.objStart = s.pos
+			}
+			s.depth++
+		case '}', ']':
+			s.depth--
+			if s.depth == 1 && ch == '}' {
+				obj := make(json.RawMessage, s.pos+1-s.objStart)
+				copy(obj, s.buf[s.objStart:s.pos+1])
+				out = append(out, obj)
+			}
+		}
+	}
+
+	return out
+}
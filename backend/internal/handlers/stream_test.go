@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+func TestJSONArraySplitter(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"empty array", `[]`, nil},
+		{"flat", `[{"a":1},{"b":2}]`, []string{`{"a":1}`, `{"b":2}`}},
+		{"fenced", "```json\n[{\"a\":1}]\n```", []string{`{"a":1}`}},
+		{"nested", `[{"a":{"b":[1,{"c":2}]}},{"d":[]}]`, []string{`{"a":{"b":[1,{"c":2}]}}`, `{"d":[]}`}},
+		{"brackets in strings", `[{"t":"} ] { ["},{"u":"x"}]`, []string{`{"t":"} ] { ["}`, `{"u":"x"}`}},
+		{"escaped quotes", `[{"t":"say \"}\" \\"},{"u":"\\\\"}]`, []string{`{"t":"say \"}\" \\"}`, `{"u":"\\\\"}`}},
+		{"multibyte", `[{"title":"Хакатон «Астана» ₸"}]`, []string{`{"title":"Хакатон «Астана» ₸"}`}},
+		{"whitespace", "[\n  {\"a\": 1},\n  {\"b\": 2}\n]", []string{`{"a": 1}`, `{"b": 2}`}},
+		{"truncated", `[{"a":1},{"b":`, []string{`{"a":1}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// One split at every byte boundary, including inside UTF-8
+			// sequences and escapes.
+			for i := 0; i <= len(tt.input); i++ {
+				var s jsonArraySplitter
+				got := s.Feed(tt.input[:i])
+				got = append(got, s.Feed(tt.input[i:])...)
+				checkObjects(t, "split at "+strconv.Itoa(i), got, tt.want)
+			}
+
+			var s jsonArraySplitter
+			var got []json.RawMessage
+			for i := 0; i < len(tt.input); i++ {
+				got = append(got, s.Feed(tt.input[i:i+1])...)
+			}
+			checkObjects(t, "byte by byte", got, tt.want)
+		})
+	}
+}
+
+func checkObjects(t *testing.T, how string, got []json.RawMessage, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d objects %q, want %d", how, len(got), got, len(want))
+	}
+	for i := range got {
+		if string(got[i]) != want[i] {
+			t.Errorf("%s: object %d = %s, want %s", how, i, got[i], want[i])
+		}
+		if !json.Valid(got[i]) {
+			t.Errorf("%s: object %d is not valid JSON: %s", how, i, got[i])
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"hackflow-api/internal/models"
+	"hackflow-api/internal/taxonomy"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagCount is a tag of the taxonomy with the number of approved hackathons
+// labelled with it.
+type TagCount struct {
+	Slug  string            `json:"slug"`
+	Name  string            `json:"name"`
+	Names map[string]string `json:"names"`
+	Count int64             `json:"count"`
+}
+
+// GetTags handles GET /api/tags?lang=ru. Tags are sorted by the number of
+// approved hackathons; name is given in lang (by default the default
+// region's language).
+func (h *Handler) GetTags(c *gin.Context) {
+	ctx := c.Request.Context()
+	lang := strings.ToLower(strings.TrimSpace(c.Query("lang")))
+	if lang == "" && h.Regions != nil {
+		if loc, err := h.Regions.Resolve("", ""); err == nil {
+			lang = loc.Lang
+		}
+	}
+
+	var tags []models.Tag
+	if err := h.DB.WithContext(ctx).Order("slug").Find(&tags).Error; err != nil {
+		slog.Error("Failed to fetch tags", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data"})
+		return
+	}
+
+	var counts []struct {
+		Slug  string
+		Count int64
+	}
+	if err := h.DB.WithContext(ctx).Raw(`
+		SELECT t.slug, COUNT(DISTINCT h.id) AS count
+		FROM tags t
+		JOIN hackathon_tags ht ON ht.tag_id = t.id
+		JOIN hackathons h ON h.id = ht.hackathon_id AND h.moderation_status = ? AND h.deleted_at IS NULL
+		GROUP BY t.slug`, models.ModerationApproved).Scan(&counts).Error; err != nil {
+		slog.Error("Failed to count hackathons per tag", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data"})
+		return
+	}
+	bySlug := make(map[string]int64, len(counts))
+	for _, cnt := range counts {
+		bySlug[cnt.Slug] = cnt.Count
+	}
+
+	out := make([]TagCount, 0, len(tags))
+	for _, stored := range tags {
+		// Теги, убранные из справочника, остаются в таблице, но не показываются
+		tag, ok := h.Tags.Find(stored.Slug)
+		if !ok {
+			if h.Tags != nil {
+				continue
+			}
+			tag = taxonomy.Tag{Slug: stored.Slug, Names: stored.Names}
+		}
+		out = append(out, TagCount{
+			Slug:  tag.Slug,
+			Name:  tag.Name(lang),
+			Names: tag.Names,
+			Count: bySlug[tag.Slug],
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Count > out[j].Count })
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,143 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// cassette is a single recorded request/response pair stored as JSON.
+type cassette struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+		Body   string `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		Status int         `json:"status"`
+		Header http.Header `json:"header"`
+		// Body is base64-encoded so binary responses (images) survive.
+		Body []byte `json:"body"`
+	} `json:"response"`
+}
+
+// secretFieldRe matches credentials that must never end up in cassettes
+// (e.g. Tavily's "api_key" body field).
+var secretFieldRe = regexp.MustCompile(`"(api_key|apiKey|token)"\s*:\s*"[^"]*"`)
+
+func redact(body []byte) []byte {
+	return secretFieldRe.ReplaceAll(body, []byte(`"$1":"REDACTED"`))
+}
+
+// cassetteKey identifies a request by method, URL and redacted body, so a
+// replay matches regardless of which API key was used to record it.
+func cassetteKey(method, url string, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", method, url)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))[:24] + ".json"
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// Recorder performs real requests through Base and saves every response into
+// Dir as a cassette.
+type Recorder struct {
+	Dir  string
+	Base http.RoundTripper
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	redacted := redact(body)
+
+	res, err := r.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	var c cassette
+	c.Request.Method = req.Method
+	c.Request.URL = req.URL.String()
+	c.Request.Body = string(redacted)
+	c.Response.Status = res.StatusCode
+	c.Response.Header = res.Header
+	c.Response.Body = resBody
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(r.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(r.Dir, cassetteKey(req.Method, c.Request.URL, redacted))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return nil, fmt.Errorf("httpclient: failed to write cassette: %w", err)
+	}
+
+	return res, nil
+}
+
+// Replayer serves responses recorded by Recorder and fails for any request
+// that has no cassette, so tests never hit the network by accident.
+type Replayer struct {
+	Dir string
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(r.Dir, cassetteKey(req.Method, req.URL.String(), redact(body)))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: no cassette for %s %s: %w", req.Method, req.URL.Redacted(), err)
+	}
+
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("httpclient: corrupt cassette %s: %w", path, err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", c.Response.Status, http.StatusText(c.Response.Status)),
+		StatusCode:    c.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        c.Response.Header,
+		Body:          io.NopCloser(bytes.NewReader(c.Response.Body)),
+		ContentLength: int64(len(c.Response.Body)),
+		Request:       req,
+	}, nil
+}
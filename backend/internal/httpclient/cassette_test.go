@@ -0,0 +1,60 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"echo":` + string(body) + `}`))
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+
+	recorder := New(Options{Transport: &Recorder{Dir: dir, Base: http.DefaultTransport}})
+	recorded := post(t, recorder, srv.URL, `{"api_key":"secret-1","q":"hack"}`)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("got %d cassettes, want 1", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), "secret-1") {
+		t.Error("cassette contains the API key")
+	}
+
+	srv.Close()
+	replayer := New(Options{Transport: &Replayer{Dir: dir}})
+	// Another key must hit the same cassette.
+	if got := post(t, replayer, srv.URL, `{"api_key":"secret-2","q":"hack"}`); got != recorded {
+		t.Errorf("replayed %q, want %q", got, recorded)
+	}
+
+	res, err := replayer.Post(srv.URL, "application/json", strings.NewReader(`{"q":"other"}`))
+	if err == nil {
+		res.Body.Close()
+		t.Error("replay of an unrecorded request succeeded, want error")
+	}
+}
+
+func post(t *testing.T, c *http.Client, url, body string) string {
+	t.Helper()
+	res, err := c.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
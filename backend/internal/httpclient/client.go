@@ -0,0 +1,94 @@
+// Package httpclient builds the outbound HTTP client shared by the scraper and
+// the API: per-call timeouts, retries with exponential backoff on 429/5xx
+// (honoring Retry-After), a fixed User-Agent and optional record/replay of
+// traffic for tests and offline development.
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"hackflow-api/internal/config"
+)
+
+// Options configures a client built by New.
+type Options struct {
+	// Timeout bounds a whole call, including retries and reading the body.
+	Timeout time.Duration
+	// AttemptTimeout bounds a single attempt, so one hung connection does not
+	// eat the whole Timeout and leave no room for a retry. Zero disables it.
+	AttemptTimeout time.Duration
+	MaxRetries     int
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	UserAgent      string
+	// Transport is the underlying transport; http.DefaultTransport if nil.
+	Transport http.RoundTripper
+}
+
+// DefaultOptions returns sensible defaults for calls to third-party APIs.
+func DefaultOptions() Options {
+	return Options{
+		Timeout:        30 * time.Second,
+		AttemptTimeout: 10 * time.Second,
+		MaxRetries:     3,
+		BaseDelay:      500 * time.Millisecond,
+		MaxDelay:       10 * time.Second,
+		UserAgent:      "HackFlowBot/1.0 (+https://github.com/slager2/HackFlow-)",
+	}
+}
+
+// New creates an *http.Client with retry and User-Agent handling.
+func New(opts Options) *http.Client {
+	base := opts.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &http.Client{
+		Timeout: opts.Timeout,
+		Transport: &retryTransport{
+			base:           base,
+			attemptTimeout: opts.AttemptTimeout,
+			maxRetries:     opts.MaxRetries,
+			baseDelay:      opts.BaseDelay,
+			maxDelay:       opts.MaxDelay,
+			userAgent:      opts.UserAgent,
+		},
+	}
+}
+
+// NewFromConfig builds the client from application configuration, wiring in
+// the recording or replaying transport when HTTP_RECORD_MODE is set.
+func NewFromConfig(cfg *config.Config) (*http.Client, error) {
+	opts := DefaultOptions()
+	opts.Timeout = cfg.HTTPTimeout
+	opts.AttemptTimeout = cfg.HTTPAttemptTimeout
+	opts.MaxRetries = cfg.HTTPMaxRetries
+	if cfg.HTTPUserAgent != "" {
+		opts.UserAgent = cfg.HTTPUserAgent
+	}
+
+	if cfg.HTTPRecordMode != "" && cfg.HTTPCassetteDir == "" {
+		return nil, fmt.Errorf("HTTP_RECORD_MODE=%s requires HTTP_CASSETTE_DIR", cfg.HTTPRecordMode)
+	}
+
+	switch cfg.HTTPRecordMode {
+	case "":
+	case "record":
+		opts.Transport = &Recorder{Dir: cfg.HTTPCassetteDir, Base: http.DefaultTransport}
+	case "replay":
+		if _, err := os.Stat(cfg.HTTPCassetteDir); err != nil {
+			return nil, fmt.Errorf("cassette directory: %w", err)
+		}
+		opts.Transport = &Replayer{Dir: cfg.HTTPCassetteDir}
+		// Recorded responses are final; retrying them is pointless.
+		opts.MaxRetries = 0
+	default:
+		return nil, fmt.Errorf("unknown HTTP_RECORD_MODE %q (expected record or replay)", cfg.HTTPRecordMode)
+	}
+
+	return New(opts), nil
+}
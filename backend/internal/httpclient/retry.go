@@ -0,0 +1,134 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type retryTransport struct {
+	base           http.RoundTripper
+	attemptTimeout time.Duration
+	maxRetries     int
+	baseDelay      time.Duration
+	maxDelay       time.Duration
+	userAgent      string
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+
+	for attempt := 0; ; attempt++ {
+		res, err := t.attempt(req)
+		if attempt >= t.maxRetries || !shouldRetry(req, res, err) {
+			return res, err
+		}
+
+		delay := t.backoff(attempt, res)
+		if res != nil {
+			// Drain so the connection can be reused.
+			io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+			res.Body.Close()
+		}
+
+		// Requests with a body can only be retried if it can be replayed.
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return nil, errors.New("httpclient: cannot retry request with non-rewindable body")
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		slog.Debug("Retrying HTTP request", "url", req.URL.Redacted(), "attempt", attempt+1, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt performs one try bounded by attemptTimeout. The deadline stays
+// attached to the response body until the caller closes it.
+func (t *retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.attemptTimeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.attemptTimeout)
+	res, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	res.Body = &cancelBody{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func shouldRetry(req *http.Request, res *http.Response, err error) bool {
+	if err != nil {
+		// The caller gave up; retrying would only waste its budget. A timed
+		// out attempt is retried as long as the caller still waits.
+		return !errors.Is(err, context.Canceled) && req.Context().Err() == nil
+	}
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+}
+
+// backoff returns the delay before the next attempt: the server's Retry-After
+// if present, otherwise exponential backoff with full jitter. Both are capped
+// by maxDelay.
+func (t *retryTransport) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if d, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			return min(d, t.maxDelay)
+		}
+	}
+
+	d := t.baseDelay << attempt
+	if d <= 0 || d > t.maxDelay {
+		d = t.maxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d) + 1
+}
+
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,290 @@
+// Package llmusage records Gemini token usage per caller, model and day and
+// enforces daily token budgets.
+package llmusage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"hackflow-api/internal/config"
+	"hackflow-api/internal/models"
+
+	"github.com/google/generative-ai-go/genai"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Callers of the LLM, used as the accounting and budget key.
+const (
+	CallerSearch     = "search"
+	CallerScraper    = "scraper"
+	CallerClassifier = "classifier"
+)
+
+// ErrBudgetExceeded is returned by Allow once a caller has used up its daily
+// token budget.
+var ErrBudgetExceeded = errors.New("daily LLM token budget exceeded")
+
+// Usage is the token count of one or more LLM calls.
+type Usage struct {
+	PromptTokens     int64
+	CandidatesTokens int64
+	TotalTokens      int64
+}
+
+// FromResponse extracts the usage metadata of a Gemini response. Responses
+// without metadata count as zero tokens.
+func FromResponse(resp *genai.GenerateContentResponse) Usage {
+	if resp == nil {
+		return Usage{}
+	}
+	return FromMetadata(resp.UsageMetadata)
+}
+
+// FromMetadata converts Gemini usage metadata.
+func FromMetadata(m *genai.UsageMetadata) Usage {
+	if m == nil {
+		return Usage{}
+	}
+	return Usage{
+		PromptTokens:     int64(m.PromptTokenCount),
+		CandidatesTokens: int64(m.CandidatesTokenCount),
+		TotalTokens:      int64(m.TotalTokenCount),
+	}
+}
+
+type key struct {
+	caller string
+	model  string
+}
+
+type counters struct {
+	requests int64
+	Usage
+}
+
+// Tracker records usage in Postgres (shared by all replicas and processes)
+// and keeps per-process counters for metrics. A nil *Tracker records nothing
+// and allows every call, so callers need no nil checks.
+type Tracker struct {
+	db *gorm.DB
+	// budgets maps a caller to its daily token budget; 0 or absent means unlimited
+	budgets map[string]int64
+	// now is the clock that decides the accounting day
+	now func() time.Time
+
+	mu       sync.Mutex
+	totals   map[key]*counters
+	exceeded map[string]int64
+	// day and used are today's tokens per caller when there is no db
+	day  string
+	used map[string]int64
+}
+
+// New creates a tracker. db may be nil to keep counters in memory only, in
+// which case budgets apply per process.
+func New(db *gorm.DB, budgets map[string]int64) *Tracker {
+	return &Tracker{
+		db:       db,
+		budgets:  budgets,
+		now:      time.Now,
+		totals:   make(map[key]*counters),
+		exceeded: make(map[string]int64),
+		used:     make(map[string]int64),
+	}
+}
+
+func (t *Tracker) today() string {
+	return t.now().UTC().Format("2006-01-02")
+}
+
+// rollover starts a new day of in-memory usage. t.mu must be held.
+func (t *Tracker) rollover() {
+	if day := t.today(); day != t.day {
+		t.day = day
+		clear(t.used)
+	}
+}
+
+// Record adds one call of caller to model with the given usage. Storage
+// errors are logged only: accounting must never fail the call itself.
+func (t *Tracker) Record(ctx context.Context, caller, model string, u Usage) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	c, ok := t.totals[key{caller, model}]
+	if !ok {
+		c = &counters{}
+		t.totals[key{caller, model}] = c
+	}
+	c.requests++
+	c.PromptTokens += u.PromptTokens
+	c.CandidatesTokens += u.CandidatesTokens
+	c.TotalTokens += u.TotalTokens
+	t.rollover()
+	t.used[caller] += u.TotalTokens
+	t.mu.Unlock()
+
+	slog.Debug("LLM usage", "caller", caller, "model", model, "prompt_tokens", u.PromptTokens, "candidates_tokens", u.CandidatesTokens, "total_tokens", u.TotalTokens)
+
+	if t.db == nil {
+		return
+	}
+	row := models.LLMUsage{
+		Day:              t.today(),
+		Caller:           caller,
+		Model:            model,
+		Requests:         1,
+		PromptTokens:     u.PromptTokens,
+		CandidatesTokens: u.CandidatesTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+	// the request context may already be canceled, usage must still be stored
+	err := t.db.WithContext(context.WithoutCancel(ctx)).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "caller"}, {Name: "model"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"requests":          gorm.Expr("llm_usages.requests + EXCLUDED.requests"),
+			"prompt_tokens":     gorm.Expr("llm_usages.prompt_tokens + EXCLUDED.prompt_tokens"),
+			"candidates_tokens": gorm.Expr("llm_usages.candidates_tokens + EXCLUDED.candidates_tokens"),
+			"total_tokens":      gorm.Expr("llm_usages.total_tokens + EXCLUDED.total_tokens"),
+			"updated_at":        time.Now(),
+		}),
+	}).Create(&row).Error
+	if err != nil {
+		slog.Warn("Failed to record LLM usage", "caller", caller, "model", model, "error", err)
+	}
+}
+
+// Budget returns the daily token budget of caller (0 means unlimited).
+func (t *Tracker) Budget(caller string) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.budgets[caller]
+}
+
+// Allow returns ErrBudgetExceeded if caller has used up today's budget. If
+// today's usage cannot be read the call is allowed.
+func (t *Tracker) Allow(ctx context.Context, caller string) error {
+	budget := t.Budget(caller)
+	if budget <= 0 {
+		return nil
+	}
+
+	used, err := t.Today(ctx, caller)
+	if err != nil {
+		slog.Warn("Failed to read LLM usage, allowing call", "caller", caller, "error", err)
+		return nil
+	}
+	if used < budget {
+		return nil
+	}
+
+	t.mu.Lock()
+	t.exceeded[caller]++
+	t.mu.Unlock()
+	slog.Warn("LLM token budget exceeded", "caller", caller, "used", used, "budget", budget)
+	return fmt.Errorf("%s: %w (%d/%d tokens)", caller, ErrBudgetExceeded, used, budget)
+}
+
+// Today returns the total tokens caller has used today (UTC).
+func (t *Tracker) Today(ctx context.Context, caller string) (int64, error) {
+	if t == nil {
+		return 0, nil
+	}
+	if t.db == nil {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.rollover()
+		return t.used[caller], nil
+	}
+
+	var total int64
+	err := t.db.WithContext(ctx).Model(&models.LLMUsage{}).
+		Where("day = ? AND caller = ?", t.today(), caller).
+		Select("COALESCE(SUM(total_tokens), 0)").Scan(&total).Error
+	return total, err
+}
+
+// History returns the stored usage rows of the last days days, newest first.
+func (t *Tracker) History(ctx context.Context, days int) ([]models.LLMUsage, error) {
+	if t == nil || t.db == nil {
+		return []models.LLMUsage{}, nil
+	}
+
+	since := t.now().UTC().AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+	rows := []models.LLMUsage{}
+	err := t.db.WithContext(ctx).Where("day >= ?", since).
+		Order("day DESC, caller, model").Find(&rows).Error
+	return rows, err
+}
+
+// WriteMetrics writes the per-process counters in the Prometheus text
+// exposition format.
+func (t *Tracker) WriteMetrics(w io.Writer) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keys := make([]key, 0, len(t.totals))
+	for k := range t.totals {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].caller != keys[j].caller {
+			return keys[i].caller < keys[j].caller
+		}
+		return keys[i].model < keys[j].model
+	})
+
+	fmt.Fprintln(w, "# HELP hackflow_llm_requests_total LLM calls made by this process.")
+	fmt.Fprintln(w, "# TYPE hackflow_llm_requests_total counter")
+	for _, k := range keys {
+		fmt.Fprintf(w, "hackflow_llm_requests_total{caller=%q,model=%q} %d\n", k.caller, k.model, t.totals[k].requests)
+	}
+
+	fmt.Fprintln(w, "# HELP hackflow_llm_tokens_total LLM tokens used by this process.")
+	fmt.Fprintln(w, "# TYPE hackflow_llm_tokens_total counter")
+	for _, k := range keys {
+		c := t.totals[k]
+		fmt.Fprintf(w, "hackflow_llm_tokens_total{caller=%q,model=%q,kind=\"prompt\"} %d\n", k.caller, k.model, c.PromptTokens)
+		fmt.Fprintf(w, "hackflow_llm_tokens_total{caller=%q,model=%q,kind=\"candidates\"} %d\n", k.caller, k.model, c.CandidatesTokens)
+	}
+
+	callers := make([]string, 0, len(t.budgets))
+	for caller := range t.budgets {
+		callers = append(callers, caller)
+	}
+	sort.Strings(callers)
+
+	fmt.Fprintln(w, "# HELP hackflow_llm_budget_tokens Daily LLM token budget (0 means unlimited).")
+	fmt.Fprintln(w, "# TYPE hackflow_llm_budget_tokens gauge")
+	for _, caller := range callers {
+		fmt.Fprintf(w, "hackflow_llm_budget_tokens{caller=%q} %d\n", caller, t.budgets[caller])
+	}
+
+	fmt.Fprintln(w, "# HELP hackflow_llm_budget_exceeded_total Calls refused because the daily budget was used up.")
+	fmt.Fprintln(w, "# TYPE hackflow_llm_budget_exceeded_total counter")
+	for _, caller := range callers {
+		fmt.Fprintf(w, "hackflow_llm_budget_exceeded_total{caller=%q} %d\n", caller, t.exceeded[caller])
+	}
+}
+
+// NewFromConfig creates a tracker with the LLM_BUDGET_* budgets from cfg.
+func NewFromConfig(db *gorm.DB, cfg *config.Config) *Tracker {
+	return New(db, map[string]int64{
+		CallerSearch:     int64(cfg.LLMBudgetSearch),
+		CallerScraper:    int64(cfg.LLMBudgetScraper),
+		CallerClassifier: int64(cfg.LLMBudgetClassifier),
+	})
+}
@@ -0,0 +1,160 @@
+package llmusage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"hackflow-api/internal/models"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// clock is a settable time source for the accounting day.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newTestTracker(db *gorm.DB, budgets map[string]int64) (*Tracker, *clock) {
+	c := &clock{t: time.Date(2025, 3, 1, 23, 0, 0, 0, time.UTC)}
+	t := New(db, budgets)
+	t.now = c.now
+	return t, c
+}
+
+// testDB opens the Postgres database named by TEST_DATABASE_DSN and runs the
+// test inside a transaction that is rolled back afterwards. Tests that need
+// it are skipped when the variable is not set.
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := db.Begin()
+	t.Cleanup(func() { tx.Rollback() })
+	if err := tx.AutoMigrate(&models.LLMUsage{}); err != nil {
+		t.Fatal(err)
+	}
+	// rows left by other runs must not count against the test budgets
+	if err := tx.Where("1 = 1").Delete(&models.LLMUsage{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestNilTracker(t *testing.T) {
+	var tr *Tracker
+	ctx := context.Background()
+
+	tr.Record(ctx, CallerSearch, "gemini", Usage{TotalTokens: 100})
+	if err := tr.Allow(ctx, CallerSearch); err != nil {
+		t.Errorf("Allow = %v, want nil", err)
+	}
+	if used, err := tr.Today(ctx, CallerSearch); used != 0 || err != nil {
+		t.Errorf("Today = %d, %v; want 0, nil", used, err)
+	}
+	if b := tr.Budget(CallerSearch); b != 0 {
+		t.Errorf("Budget = %d, want 0", b)
+	}
+	if rows, err := tr.History(ctx, 7); len(rows) != 0 || err != nil {
+		t.Errorf("History = %v, %v; want empty", rows, err)
+	}
+	var b strings.Builder
+	tr.WriteMetrics(&b)
+	if b.Len() != 0 {
+		t.Errorf("WriteMetrics wrote %q, want nothing", b.String())
+	}
+}
+
+// testBudget runs the budget scenario shared by the in-memory and the
+// Postgres tracker.
+func testBudget(t *testing.T, db *gorm.DB) {
+	tr, c := newTestTracker(db, map[string]int64{CallerScraper: 1000})
+	ctx := context.Background()
+
+	if err := tr.Allow(ctx, CallerScraper); err != nil {
+		t.Fatalf("Allow before any call = %v", err)
+	}
+	tr.Record(ctx, CallerScraper, "gemini-flash", Usage{PromptTokens: 500, CandidatesTokens: 100, TotalTokens: 600})
+	tr.Record(ctx, CallerSearch, "gemini-flash", Usage{TotalTokens: 5000})
+	if err := tr.Allow(ctx, CallerScraper); err != nil {
+		t.Errorf("Allow at 600/1000 = %v", err)
+	}
+
+	tr.Record(ctx, CallerScraper, "gemini-pro", Usage{TotalTokens: 400})
+	if used, err := tr.Today(ctx, CallerScraper); used != 1000 || err != nil {
+		t.Errorf("Today = %d, %v; want 1000 across models", used, err)
+	}
+	if err := tr.Allow(ctx, CallerScraper); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Allow at 1000/1000 = %v, want ErrBudgetExceeded", err)
+	}
+	// search has no budget and is not affected by the scraper's
+	if err := tr.Allow(ctx, CallerSearch); err != nil {
+		t.Errorf("Allow for an unlimited caller = %v", err)
+	}
+
+	// a new UTC day starts with a fresh budget
+	c.t = c.t.Add(2 * time.Hour)
+	if err := tr.Allow(ctx, CallerScraper); err != nil {
+		t.Errorf("Allow after rollover = %v", err)
+	}
+	if used, _ := tr.Today(ctx, CallerScraper); used != 0 {
+		t.Errorf("Today after rollover = %d, want 0", used)
+	}
+
+	var b strings.Builder
+	tr.WriteMetrics(&b)
+	for _, want := range []string{
+		`hackflow_llm_requests_total{caller="scraper",model="gemini-flash"} 1`,
+		`hackflow_llm_tokens_total{caller="scraper",model="gemini-flash",kind="prompt"} 500`,
+		`hackflow_llm_budget_tokens{caller="scraper"} 1000`,
+		`hackflow_llm_budget_exceeded_total{caller="scraper"} 1`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("metrics lack %q:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestBudgetInMemory(t *testing.T) {
+	testBudget(t, nil)
+}
+
+func TestBudgetPostgres(t *testing.T) {
+	testBudget(t, testDB(t))
+}
+
+func TestRecordPostgres(t *testing.T) {
+	db := testDB(t)
+	tr, c := newTestTracker(db, nil)
+	ctx := context.Background()
+
+	tr.Record(ctx, CallerSearch, "gemini-flash", Usage{PromptTokens: 10, CandidatesTokens: 5, TotalTokens: 15})
+	tr.Record(ctx, CallerSearch, "gemini-flash", Usage{PromptTokens: 20, CandidatesTokens: 5, TotalTokens: 25})
+	c.t = c.t.Add(2 * time.Hour)
+	tr.Record(ctx, CallerSearch, "gemini-flash", Usage{TotalTokens: 7})
+
+	rows, err := tr.History(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("History = %d rows, want one per day", len(rows))
+	}
+	if r := rows[1]; r.Day != "2025-03-01" || r.Requests != 2 || r.PromptTokens != 30 || r.TotalTokens != 40 {
+		t.Errorf("first day = %+v, want 2 requests, 30 prompt and 40 total tokens", r)
+	}
+	if r := rows[0]; r.Day != "2025-03-02" || r.Requests != 1 || r.TotalTokens != 7 {
+		t.Errorf("second day = %+v, want 1 request and 7 tokens", r)
+	}
+}
@@ -0,0 +1,129 @@
+// Package media downloads remote images, stores them in a BlobStore and
+// generates thumbnails.
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"strings"
+
+	"hackflow-api/internal/storage"
+)
+
+const (
+	// maxImageBytes caps downloads; posters are rarely larger than a few MB.
+	maxImageBytes = 10 << 20
+	// maxImagePixels caps the decoded size: a small compressed file can
+	// declare huge dimensions and exhaust memory when decoded.
+	maxImagePixels = 40_000_000
+	// ThumbnailWidth is the width of generated thumbnails in pixels.
+	ThumbnailWidth = 400
+)
+
+// Image is a stored image with its thumbnail.
+type Image struct {
+	URL          string
+	ThumbnailURL string
+}
+
+// Processor downloads and stores images.
+type Processor struct {
+	Store  storage.BlobStore
+	Client *http.Client
+}
+
+// NewProcessor creates a Processor using client for downloads.
+func NewProcessor(store storage.BlobStore, client *http.Client) *Processor {
+	return &Processor{Store: store, Client: client}
+}
+
+// Save downloads sourceURL, stores the original and a JPEG thumbnail, and
+// returns their public URLs. Keys are derived from the source URL, so an
+// image is only downloaded once.
+func (p *Processor) Save(ctx context.Context, sourceURL string) (*Image, error) {
+	id := hashKey(sourceURL)
+	originalKey := "images/" + id[:2] + "/" + id
+	thumbKey := "thumbs/" + id[:2] + "/" + id + ".jpg"
+
+	if ok, err := p.Store.Exists(ctx, thumbKey); err == nil && ok {
+		return &Image{URL: p.Store.URL(originalKey), ThumbnailURL: p.Store.URL(thumbKey)}, nil
+	}
+
+	data, contentType, err := p.download(ctx, sourceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image header: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > maxImagePixels {
+		return nil, fmt.Errorf("image dimensions %dx%d exceed %d pixels", cfg.Width, cfg.Height, maxImagePixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	originalURL, err := p.Store.Put(ctx, originalKey, bytes.NewReader(data), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store image: %w", err)
+	}
+
+	var thumb bytes.Buffer
+	if err := jpeg.Encode(&thumb, Thumbnail(img, ThumbnailWidth), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	thumbURL, err := p.Store.Put(ctx, thumbKey, &thumb, "image/jpeg")
+	if err != nil {
+		return nil, fmt.Errorf("failed to store thumbnail: %w", err)
+	}
+
+	return &Image{URL: originalURL, ThumbnailURL: thumbURL}, nil
+}
+
+func (p *Processor) download(ctx context.Context, sourceURL string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	contentType := res.Header.Get("Content-Type")
+	if contentType != "" && !strings.HasPrefix(contentType, "image/") {
+		return nil, "", fmt.Errorf("unexpected content type %q", contentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxImageBytes+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > maxImageBytes {
+		return nil, "", fmt.Errorf("image exceeds %d bytes", maxImageBytes)
+	}
+
+	return data, contentType, nil
+}
+
+func hashKey(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:16])
+}
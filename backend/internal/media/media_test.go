@@ -0,0 +1,158 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"hackflow-api/internal/storage"
+)
+
+// response is what the test server sends for a path.
+type response struct {
+	contentType string
+	body        []byte
+}
+
+func newTestProcessor(t *testing.T, files map[string]response) (*Processor, *httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		f, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header()["Content-Type"] = []string{f.contentType}
+		w.Write(f.body)
+	}))
+	t.Cleanup(srv.Close)
+
+	store, err := storage.NewLocalStore(t.TempDir(), "/media")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewProcessor(store, srv.Client()), srv, &calls
+}
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pngHeader returns a PNG that declares w×h pixels but carries no image
+// data, the shape of a decompression bomb as seen by DecodeConfig.
+func pngHeader(w, h uint32) []byte {
+	var ihdr bytes.Buffer
+	ihdr.WriteString("IHDR")
+	binary.Write(&ihdr, binary.BigEndian, w)
+	binary.Write(&ihdr, binary.BigEndian, h)
+	ihdr.Write([]byte{8, 6, 0, 0, 0}) // 8-bit RGBA, no interlace
+
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+	binary.Write(&buf, binary.BigEndian, uint32(ihdr.Len()-4))
+	buf.Write(ihdr.Bytes())
+	binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(ihdr.Bytes()))
+	return buf.Bytes()
+}
+
+func TestSave(t *testing.T) {
+	p, srv, calls := newTestProcessor(t, map[string]response{
+		"/poster.png": {"image/png", encodePNG(t, 800, 600)},
+	})
+	ctx := context.Background()
+
+	img, err := p.Save(ctx, srv.URL+"/poster.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(img.URL, "/media/images/") || !strings.HasPrefix(img.ThumbnailURL, "/media/thumbs/") {
+		t.Fatalf("Save = %+v, want URLs under /media", img)
+	}
+
+	dir := p.Store.(*storage.LocalStore).Dir
+	data, err := os.ReadFile(filepath.Join(dir, strings.TrimPrefix(img.ThumbnailURL, "/media/")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	thumb, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("thumbnail is not a JPEG: %v", err)
+	}
+	if b := thumb.Bounds(); b.Dx() != ThumbnailWidth || b.Dy() != 300 {
+		t.Errorf("thumbnail is %dx%d, want %dx300", b.Dx(), b.Dy(), ThumbnailWidth)
+	}
+
+	// A stored image is not downloaded again.
+	again, err := p.Save(ctx, srv.URL+"/poster.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *again != *img || calls.Load() != 1 {
+		t.Errorf("second Save = %+v after %d downloads, want %+v after 1", again, calls.Load(), img)
+	}
+}
+
+func TestSaveRejects(t *testing.T) {
+	oversized := append(encodePNG(t, 4, 4), make([]byte, maxImageBytes)...)
+	p, srv, _ := newTestProcessor(t, map[string]response{
+		"/page.html": {"text/html; charset=utf-8", []byte("<html></html>")},
+		"/huge.png":  {"image/png", oversized},
+		"/bomb.png":  {"image/png", pngHeader(10_000, 5_000)},
+		"/empty.png": {"image/png", pngHeader(0, 10)},
+		"/text.png":  {"image/png", []byte("not an image")},
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/page.html", "unexpected content type"},
+		{"/huge.png", "exceeds"},
+		{"/bomb.png", "exceed 40000000 pixels"},
+		{"/empty.png", "decode image header"},
+		{"/text.png", "decode image header"},
+		{"/missing.png", "unexpected status code: 404"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			_, err := p.Save(context.Background(), srv.URL+tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Save(%s) = %v, want error containing %q", tt.path, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveWithoutContentType(t *testing.T) {
+	p, srv, _ := newTestProcessor(t, map[string]response{
+		"/poster": {"", encodePNG(t, 10, 10)},
+	})
+	if _, err := p.Save(context.Background(), srv.URL+"/poster"); err != nil {
+		t.Errorf("Save without Content-Type = %v, want it decoded by content", err)
+	}
+}
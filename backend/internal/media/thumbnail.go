@@ -0,0 +1,59 @@
+package media
+
+import (
+	"image"
+	"image/color"
+)
+
+// Thumbnail scales img down to the given width, keeping the aspect ratio.
+// Each destination pixel is the average of the source pixels it covers
+// (box filter), which is good enough for poster previews. Images narrower
+// than width are returned unchanged.
+func Thumbnail(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	srcW, srcH := b.Dx(), b.Dy()
+	if srcW <= width || srcW == 0 || srcH == 0 {
+		return img
+	}
+
+	height := srcH * width / srcW
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := b.Min.Y + y*srcH/height
+		y1 := b.Min.Y + (y+1)*srcH/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0 := b.Min.X + x*srcW/width
+			x1 := b.Min.X + (x+1)*srcW/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					bl += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(bl / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,53 @@
+package media
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestThumbnailDimensions(t *testing.T) {
+	tests := []struct {
+		w, h          int
+		width         int
+		wantW, wantH  int
+		wantUnchanged bool
+	}{
+		{800, 600, 400, 400, 300, false},
+		{1000, 3000, 400, 400, 1200, false},
+		{4000, 10, 400, 400, 1, false},
+		{401, 401, 400, 400, 400, false},
+		{400, 300, 400, 400, 300, true},
+		{120, 80, 400, 120, 80, true},
+	}
+	for _, tt := range tests {
+		src := image.NewRGBA(image.Rect(0, 0, tt.w, tt.h))
+		got := Thumbnail(src, tt.width)
+		if b := got.Bounds(); b.Dx() != tt.wantW || b.Dy() != tt.wantH {
+			t.Errorf("Thumbnail(%dx%d, %d) is %dx%d, want %dx%d", tt.w, tt.h, tt.width, b.Dx(), b.Dy(), tt.wantW, tt.wantH)
+		}
+		if unchanged := got == image.Image(src); unchanged != tt.wantUnchanged {
+			t.Errorf("Thumbnail(%dx%d, %d) returned the source = %v, want %v", tt.w, tt.h, tt.width, unchanged, tt.wantUnchanged)
+		}
+	}
+}
+
+func TestThumbnailAverages(t *testing.T) {
+	// Alternating black and white columns average to mid grey.
+	src := image.NewGray(image.Rect(10, 20, 18, 24))
+	for y := 20; y < 24; y++ {
+		for x := 10; x < 18; x++ {
+			if x%2 == 0 {
+				src.SetGray(x, y, color.Gray{255})
+			}
+		}
+	}
+	got := Thumbnail(src, 4)
+	if b := got.Bounds(); b != image.Rect(0, 0, 4, 2) {
+		t.Fatalf("Thumbnail bounds = %v, want (0,0)-(4,2)", b)
+	}
+	r, g, b, a := got.At(1, 1).RGBA()
+	if r>>8 != 127 || g>>8 != 127 || b>>8 != 127 || a>>8 != 255 {
+		t.Errorf("pixel = %d,%d,%d,%d, want mid grey", r>>8, g>>8, b>>8, a>>8)
+	}
+}
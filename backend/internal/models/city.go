@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// City is an entry of the city reference table. It is seeded from the region
+// configuration (internal/region) on startup; hackathons reference it by slug.
+type City struct {
+	ID   uint   `json:"-" gorm:"primaryKey"`
+	Slug string `json:"slug" gorm:"not null;uniqueIndex"`
+	// Region is the code of the region the city belongs to, e.g. "kz".
+	Region string `json:"region" gorm:"not null;index"`
+	// Names holds the canonical name per language code (ru, kk, en, ...).
+	Names map[string]string `json:"names" gorm:"type:jsonb;serializer:json"`
+	// Aliases are other spellings that are normalized to this city.
+	Aliases   []string  `json:"aliases" gorm:"type:jsonb;serializer:json"`
+	Lat       float64   `json:"lat"`
+	Lon       float64   `json:"lon"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
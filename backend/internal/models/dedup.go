@@ -0,0 +1,32 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeTitle builds the deduplication key for a hackathon title: it is
+// case-insensitive, treats "ё" as "е" and ignores punctuation and spacing, so
+// "Decentrathon 5.0!" and "decentrathon 5 0" collapse to the same key.
+func NormalizeTitle(title string) string {
+	var b strings.Builder
+	b.Grow(len(title))
+
+	pendingSpace := false
+	for _, r := range strings.ToLower(title) {
+		if r == 'ё' {
+			r = 'е'
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingSpace && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			pendingSpace = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingSpace = true
+	}
+
+	return b.String()
+}
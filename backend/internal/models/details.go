@@ -0,0 +1,138 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EventDetails are the optional facts of an announcement beyond dates and
+// place: prizes, tracks, team size, fee and language. It is embedded in
+// Hackathon and in the raw model responses, which use the same JSON keys.
+type EventDetails struct {
+	// PrizePool is the total prize fund in whole PrizeCurrency units; nil if
+	// the announcement names none.
+	PrizePool     *int64 `json:"prizePool"`
+	PrizeCurrency string `json:"prizeCurrency"`
+	// Tracks are the themes or tracks as written in the announcement.
+	Tracks []string `json:"tracks" gorm:"type:jsonb;serializer:json"`
+	// TeamSizeMin and TeamSizeMax bound the team size; nil means no bound.
+	TeamSizeMin *int `json:"teamSizeMin"`
+	TeamSizeMax *int `json:"teamSizeMax"`
+	// Fee is the participation fee in whole FeeCurrency units: 0 means free,
+	// nil means not mentioned.
+	Fee         *int64 `json:"fee"`
+	FeeCurrency string `json:"feeCurrency"`
+	// Language is the ISO 639-1 code of the working language, e.g. "ru".
+	Language string `json:"language" gorm:"not null;default:'';index"`
+}
+
+// MaxTeamSize rejects team sizes that are participant counts in disguise
+// ("до 300 участников").
+const MaxTeamSize = 50
+
+// currencySigns and currencyWords map currency signs and names to ISO 4217
+// codes. Words match whole tokens; a trailing "*" matches any ending, for
+// inflected forms ("рублей", "долларов"). Short names are listed in full so
+// that "сум" does not match "сумма".
+var currencySigns = map[rune]string{'₸': "KZT", '₽': "RUB", '$': "USD", '€': "EUR", '£': "GBP"}
+
+var currencyWords = []struct {
+	code  string
+	words []string
+}{
+	{"KZT", []string{"тенге", "теңге", "тг", "tenge"}},
+	{"UZS", []string{"сум", "сума", "сумов", "so'm", "soʻm", "som"}},
+	{"KGS", []string{"сом", "сома", "сомов"}},
+	{"RUB", []string{"руб", "рубл*", "ruble*", "rouble*"}},
+	{"USD", []string{"доллар*", "dollar*"}},
+	{"EUR", []string{"евро", "euro*"}},
+}
+
+// isoCurrencies are the codes accepted as written ("usd", "AZN"): the region's
+// currencies and the ones prizes are commonly paid in.
+var isoCurrencies = map[string]bool{
+	"KZT": true, "UZS": true, "KGS": true, "TJS": true, "TMT": true,
+	"RUB": true, "BYN": true, "UAH": true, "AZN": true, "AMD": true, "GEL": true,
+	"USD": true, "EUR": true, "GBP": true, "CHF": true, "CNY": true, "JPY": true,
+	"KRW": true, "TRY": true, "AED": true, "SAR": true, "INR": true,
+}
+
+// ParseCurrency maps a currency code, name or sign ("тенге", "$", "usd") to
+// an ISO 4217 code; unrecognized text yields an empty string.
+func ParseCurrency(text string) string {
+	for _, r := range text {
+		if code, ok := currencySigns[r]; ok {
+			return code
+		}
+	}
+
+	tokens := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\'' && r != 'ʻ'
+	})
+	for _, token := range tokens {
+		token = strings.Trim(token, "'ʻ")
+		if code := strings.ToUpper(token); isoCurrencies[code] {
+			return code
+		}
+		for _, cw := range currencyWords {
+			for _, w := range cw.words {
+				if prefix, ok := strings.CutSuffix(w, "*"); ok && strings.HasPrefix(token, prefix) || token == w {
+					return cw.code
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// Normalize drops implausible values from model output: negative amounts,
+// an empty prize pool, unknown currencies, oversized or inverted team sizes,
+// blank or repeated tracks and malformed language codes.
+func (d *EventDetails) Normalize() {
+	if d.PrizePool != nil && *d.PrizePool <= 0 {
+		d.PrizePool = nil
+	}
+	d.PrizeCurrency = ParseCurrency(d.PrizeCurrency)
+	if d.PrizePool == nil {
+		d.PrizeCurrency = ""
+	}
+	if d.Fee != nil && *d.Fee < 0 {
+		d.Fee = nil
+	}
+	d.FeeCurrency = ParseCurrency(d.FeeCurrency)
+	if d.Fee == nil || *d.Fee == 0 {
+		d.FeeCurrency = ""
+	}
+
+	d.TeamSizeMin = teamSize(d.TeamSizeMin)
+	d.TeamSizeMax = teamSize(d.TeamSizeMax)
+	if d.TeamSizeMin != nil && d.TeamSizeMax != nil && *d.TeamSizeMin > *d.TeamSizeMax {
+		d.TeamSizeMin, d.TeamSizeMax = d.TeamSizeMax, d.TeamSizeMin
+	}
+
+	seen := make(map[string]bool, len(d.Tracks))
+	var tracks []string
+	for _, t := range d.Tracks {
+		t = strings.TrimSpace(t)
+		key := strings.ToLower(t)
+		if t == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		tracks = append(tracks, t)
+	}
+	d.Tracks = tracks
+
+	d.Language = strings.ToLower(strings.TrimSpace(d.Language))
+	if utf8.RuneCountInString(d.Language) != 2 || strings.Trim(d.Language, "abcdefghijklmnopqrstuvwxyz") != "" {
+		d.Language = ""
+	}
+}
+
+func teamSize(n *int) *int {
+	if n == nil || *n < 1 || *n > MaxTeamSize {
+		return nil
+	}
+	return n
+}
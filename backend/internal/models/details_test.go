@@ -0,0 +1,31 @@
+package models
+
+import "testing"
+
+func TestParseCurrency(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"KZT", "KZT"},
+		{"usd", "USD"},
+		{"AZN", "AZN"},
+		{"тенге", "KZT"},
+		{"5 000 000 тг", "KZT"},
+		{"₸", "KZT"},
+		{"US$", "USD"},
+		{"долларов США", "USD"},
+		{"рублей", "RUB"},
+		{"so'm", "UZS"},
+		{"сомов", "KGS"},
+		{"euros", "EUR"},
+		{"сумма призов", ""}, // "сум" is matched as a whole word only
+		{"abc", ""},          // not an ISO code in use
+		{"приз", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ParseCurrency(tt.text); got != tt.want {
+			t.Errorf("ParseCurrency(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
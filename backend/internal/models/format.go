@@ -0,0 +1,124 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Format is how participants take part in a hackathon.
+type Format string
+
+// Hackathon formats. An empty format means it is unknown.
+const (
+	FormatOffline Format = "offline"
+	FormatOnline  Format = "online"
+	FormatHybrid  Format = "hybrid"
+)
+
+// Audience is who a hackathon is meant for. A hackathon may be open to several
+// audiences; none means anyone.
+type Audience string
+
+// Hackathon audiences.
+const (
+	AudienceSchool        Audience = "school"
+	AudienceStudents      Audience = "students"
+	AudienceProfessionals Audience = "professionals"
+)
+
+// Online reports whether the format allows remote participation.
+func (f Format) Online() bool {
+	return f == FormatOnline || f == FormatHybrid
+}
+
+// Valid reports whether f is one of the known formats.
+func (f Format) Valid() bool {
+	return f == FormatOffline || f == FormatOnline || f == FormatHybrid
+}
+
+// Valid reports whether a is one of the known audiences.
+func (a Audience) Valid() bool {
+	return a == AudienceSchool || a == AudienceStudents || a == AudienceProfessionals
+}
+
+// ParseFormat maps the free-text format written by the model or a source
+// ("ОФЛАЙН/ОНЛАЙН", "online", "гибрид") to a Format; unrecognized text yields
+// an empty format.
+func ParseFormat(text string) Format {
+	text = strings.ToLower(text)
+	online := strings.Contains(text, "онлайн") || strings.Contains(text, "online") || strings.Contains(text, "remote")
+	offline := strings.Contains(text, "офлайн") || strings.Contains(text, "оффлайн") || strings.Contains(text, "offline") || strings.Contains(text, "очно")
+	hybrid := strings.Contains(text, "гибрид") || strings.Contains(text, "hybrid") || strings.Contains(text, "аралас")
+
+	switch {
+	case hybrid || (online && offline):
+		return FormatHybrid
+	case online:
+		return FormatOnline
+	case offline:
+		return FormatOffline
+	}
+	return ""
+}
+
+var (
+	ageRangeRe = regexp.MustCompile(`(?i)(\d{1,2})\s*(?:-|–|—|до|to)\s*(\d{1,2})`)
+	ageMinRe   = regexp.MustCompile(`(?i)(?:(\d{1,2})\s*\+|(?:от|с|from|aged|over|старше)\s*(\d{1,2})|(\d{1,2})\s*(?:жастан|лет и старше|years? and (?:older|over)))`)
+	ageMaxRe   = regexp.MustCompile(`(?i)(?:до|under|младше)\s*(\d{1,2})`)
+)
+
+var audienceWords = []struct {
+	audience Audience
+	words    []string
+}{
+	{AudienceSchool, []string{"школьник", "школ", "school", "оқушы", "мектеп"}},
+	{AudienceStudents, []string{"студент", "student", "магистрант", "бакалавр", "университет", "university"}},
+	{AudienceProfessionals, []string{"профессионал", "professional", "специалист", "разработчик", "developer", "маман"}},
+}
+
+// ParseAgeLimit extracts the age bounds and audiences from free text such as
+// "16+", "от 14 до 18 лет", "14 жастан бастап" or "Школьники и студенты".
+// Text without restrictions ("Нет ограничений") yields no bounds and no
+// audiences.
+func ParseAgeLimit(text string) (minAge, maxAge *int, audiences []Audience) {
+	lower := strings.ToLower(text)
+	for _, aw := range audienceWords {
+		for _, w := range aw.words {
+			if strings.Contains(lower, w) {
+				audiences = append(audiences, aw.audience)
+				break
+			}
+		}
+	}
+
+	// "7-11 классов" are school grades, not ages
+	if m := ageRangeRe.FindStringSubmatchIndex(lower); m != nil && !strings.HasPrefix(strings.TrimSpace(lower[m[1]:]), "класс") {
+		lo, hi := parseAge(lower[m[2]:m[3]]), parseAge(lower[m[4]:m[5]])
+		if lo != nil && hi != nil && *lo <= *hi {
+			return lo, hi, audiences
+		}
+	}
+	if m := ageMinRe.FindStringSubmatch(lower); m != nil {
+		for _, g := range m[1:] {
+			if g != "" {
+				minAge = parseAge(g)
+				break
+			}
+		}
+	}
+	if m := ageMaxRe.FindStringSubmatch(lower); m != nil {
+		maxAge = parseAge(m[1])
+	}
+	return minAge, maxAge, audiences
+}
+
+// parseAge accepts plausible participant ages only, so "до 5 человек" or
+// "2025" are not taken for ages.
+func parseAge(s string) *int {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 6 || n > 99 {
+		return nil
+	}
+	return &n
+}
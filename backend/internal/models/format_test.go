@@ -0,0 +1,37 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseAgeLimit(t *testing.T) {
+	tests := []struct {
+		text      string
+		min, max  int // 0 means no bound
+		audiences []Audience
+	}{
+		{"16+", 16, 0, nil},
+		{"от 14 до 18 лет", 14, 18, nil},
+		{"14 жастан бастап", 14, 0, nil},
+		{"Студенты", 0, 0, []Audience{AudienceStudents}},
+		{"Школьники и студенты", 0, 0, []Audience{AudienceSchool, AudienceStudents}},
+		{"students and young professionals, 18+", 18, 0, []Audience{AudienceStudents, AudienceProfessionals}},
+		{"7-11 классов", 0, 0, nil},
+		{"Нет ограничений", 0, 0, nil},
+	}
+	for _, tt := range tests {
+		minAge, maxAge, audiences := ParseAgeLimit(tt.text)
+		if deref(minAge) != tt.min || deref(maxAge) != tt.max || !slices.Equal(audiences, tt.audiences) {
+			t.Errorf("ParseAgeLimit(%q) = %d, %d, %v; want %d, %d, %v",
+				tt.text, deref(minAge), deref(maxAge), audiences, tt.min, tt.max, tt.audiences)
+		}
+	}
+}
+
+func deref(n *int) int {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
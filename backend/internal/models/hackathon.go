@@ -6,15 +6,73 @@ import (
 	"gorm.io/gorm"
 )
 
+// Moderation statuses of a hackathon.
+const (
+	ModerationPending  = "pending"
+	ModerationApproved = "approved"
+	ModerationRejected = "rejected"
+)
+
 // Hackathon represents an IT event in the database.
 type Hackathon struct {
 	gorm.Model
 	Title    string     `json:"title" gorm:"not null"`
 	Date     string     `json:"date" gorm:"not null"`
 	Deadline *time.Time `json:"deadline"`
-	Format   string     `json:"format" gorm:"not null"`
+	Format   Format     `json:"format" gorm:"not null;index"`
 	City     string     `json:"city"`
-	AgeLimit string     `json:"ageLimit"`
 	Link     string     `json:"link"`
 	Status   string     `json:"status" gorm:"not null;default:'LIVE'"`
+	// MinAge and MaxAge bound the participants' age (nil means no bound);
+	// Audiences narrow who may take part (none means anyone).
+	MinAge    *int       `json:"minAge"`
+	MaxAge    *int       `json:"maxAge"`
+	Audiences []Audience `json:"audiences" gorm:"type:jsonb;serializer:json"`
+	// City is the display text; Cities are all known host cities from the
+	// cities table, so filtering by any of them finds the event.
+	Cities []City `json:"cities" gorm:"many2many:hackathon_cities"`
+	// National events are held across a region; they are also linked to the
+	// region's national cities.
+	National bool `json:"national" gorm:"not null;default:false"`
+	// Online is derived from Format on save (online or hybrid).
+	Online bool `json:"online" gorm:"not null;default:false"`
+	EventDetails
+	// Tags are topics from the controlled taxonomy, assigned on extraction
+	// and editable by moderators.
+	Tags []Tag `json:"tags" gorm:"many2many:hackathon_tags"`
+	// TagsEditedAt is set when a moderator changes the tags, so automatic
+	// tagging never overrides the moderator, even an emptied list.
+	TagsEditedAt *time.Time `json:"-"`
+	// Organizer is the company or community running the event, if known.
+	OrganizerID *uint      `json:"-" gorm:"index"`
+	Organizer   *Organizer `json:"organizer,omitempty"`
+	// ImageURL and ThumbnailURL point to the poster stored by the scraper.
+	ImageURL     string `json:"imageUrl"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+	// ModerationStatus controls visibility: only approved hackathons are
+	// returned by the public API. AI search discoveries start as pending.
+	ModerationStatus string `json:"moderationStatus" gorm:"not null;default:'approved';index"`
+	// SourceURLs are the web pages an AI-discovered hackathon was extracted from.
+	SourceURLs []string `json:"sourceUrls,omitempty" gorm:"type:jsonb;serializer:json"`
+	// PromptVersion identifies the prompt template the fields were extracted
+	// with (see internal/prompts); empty for manually created records.
+	PromptVersion string `json:"promptVersion,omitempty"`
+	// DedupKey is the normalized title; the unique index makes concurrent
+	// inserts of the same event from several scraper replicas safe.
+	DedupKey string `json:"-" gorm:"not null;default:'';uniqueIndex:idx_hackathons_dedup_key,where:dedup_key <> ''"`
+}
+
+// BeforeSave keeps DedupKey in sync with Title and Online with Format, and
+// defaults the moderation status to approved for trusted sources such as the
+// Telegram scraper. Free-text formats are parsed into a Format.
+func (h *Hackathon) BeforeSave(tx *gorm.DB) error {
+	h.DedupKey = NormalizeTitle(h.Title)
+	if !h.Format.Valid() {
+		h.Format = ParseFormat(string(h.Format))
+	}
+	h.Online = h.Format.Online()
+	if h.ModerationStatus == "" {
+		h.ModerationStatus = ModerationApproved
+	}
+	return nil
 }
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// LLMUsage aggregates LLM calls and tokens per day, caller and model.
+type LLMUsage struct {
+	ID               uint      `json:"-" gorm:"primaryKey"`
+	Day              string    `json:"day" gorm:"size:10;not null;uniqueIndex:idx_llm_usage_day_caller_model"`
+	Caller           string    `json:"caller" gorm:"not null;uniqueIndex:idx_llm_usage_day_caller_model"`
+	Model            string    `json:"model" gorm:"not null;uniqueIndex:idx_llm_usage_day_caller_model"`
+	Requests         int64     `json:"requests" gorm:"not null;default:0"`
+	PromptTokens     int64     `json:"promptTokens" gorm:"not null;default:0"`
+	CandidatesTokens int64     `json:"candidatesTokens" gorm:"not null;default:0"`
+	TotalTokens      int64     `json:"totalTokens" gorm:"not null;default:0"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Organizer is an entry of the organizers table. Organizers are created from
+// extracted announcements and matched by their normalized name, so "Astana
+// Hub" and "ASTANA HUB" are the same organizer.
+type Organizer struct {
+	ID   uint   `json:"id" gorm:"primaryKey"`
+	Name string `json:"name" gorm:"not null"`
+	// Key is the normalized name (see NormalizeTitle).
+	Key       string    `json:"-" gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SearchCacheEntry is a persisted AI search response, keyed by the normalized
+// query and the date it was produced for.
+type SearchCacheEntry struct {
+	Key       string    `gorm:"primaryKey"`
+	Query     string    `gorm:"not null"`
+	Payload   []byte    `gorm:"type:jsonb;not null"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Tag is an entry of the topic tag table. It is seeded from the taxonomy
+// configuration (internal/taxonomy) on startup; hackathons reference it by
+// slug.
+type Tag struct {
+	ID   uint   `gorm:"primaryKey"`
+	Slug string `gorm:"not null;uniqueIndex"`
+	// Names holds the display name per language code (ru, kk, en, ...).
+	Names     map[string]string `gorm:"type:jsonb;serializer:json"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MarshalJSON encodes a tag as its slug, the same shape the search endpoints
+// use; display names are served by GET /api/tags.
+func (t Tag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Slug)
+}
+
+// UnmarshalJSON decodes a tag from its slug.
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &t.Slug)
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTagJSON(t *testing.T) {
+	h := Hackathon{Tags: []Tag{{ID: 1, Slug: "ai-ml", Names: map[string]string{"en": "AI/ML"}}, {ID: 2, Slug: "fintech"}}}
+	data, err := json.Marshal(h.Tags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `["ai-ml","fintech"]`; got != want {
+		t.Errorf("tags JSON = %s, want %s", got, want)
+	}
+
+	var tags []Tag
+	if err := json.Unmarshal(data, &tags); err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 2 || tags[0].Slug != "ai-ml" || tags[1].Slug != "fintech" {
+		t.Errorf("decoded tags = %+v", tags)
+	}
+}
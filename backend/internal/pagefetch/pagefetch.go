@@ -0,0 +1,185 @@
+// Package pagefetch downloads web pages found by the search agent and
+// extracts their readable main content, so the LLM sees more than the short
+// search snippets (dates and registration links are often missing there).
+package pagefetch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"hackflow-api/internal/cache"
+	"hackflow-api/internal/websearch"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	// maxPageBytes caps downloads; readable content beyond it is rarely useful.
+	maxPageBytes = 2 << 20
+	// charsPerToken is a conservative estimate for mixed Russian/English text.
+	charsPerToken = 3
+)
+
+// boilerplateSelectors are removed before extracting text.
+var boilerplateSelectors = "script, style, noscript, template, svg, iframe, form, nav, header, footer, aside, " +
+	"[role=navigation], [role=banner], [role=contentinfo], [aria-hidden=true], " +
+	".cookie, .cookies, .advert, .ads, .banner, .sidebar, .menu, .share, .social, .breadcrumbs"
+
+// mainSelectors are tried in order to find the main content container.
+var mainSelectors = []string{"article", "main", "[role=main]", "#content", ".content", ".post", ".entry-content"}
+
+// Fetcher downloads and caches page content.
+type Fetcher struct {
+	HTTP *http.Client
+	// TopN is the number of leading search results to fetch.
+	TopN int
+	// TokenBudget is the total size of fetched content across all pages.
+	TokenBudget int
+	cache       *cache.LRU[string, string]
+}
+
+// New creates a Fetcher caching extracted text per URL for ttl.
+func New(httpClient *http.Client, topN, tokenBudget int, ttl time.Duration) *Fetcher {
+	return &Fetcher{
+		HTTP:        httpClient,
+		TopN:        topN,
+		TokenBudget: tokenBudget,
+		cache:       cache.NewLRU[string, string](512, ttl),
+	}
+}
+
+// Enrich fetches the top results in parallel and appends the readable page
+// text to each result's snippet. Pages that fail to download keep their
+// snippet. The returned slice is a copy; results is not modified.
+func (f *Fetcher) Enrich(ctx context.Context, results []websearch.Result) []websearch.Result {
+	enriched := append([]websearch.Result(nil), results...)
+
+	n := min(f.TopN, len(results))
+	if n <= 0 || f.TokenBudget <= 0 {
+		return enriched
+	}
+	perPage := f.TokenBudget * charsPerToken / n
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		if results[i].URL == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			text, err := f.Fetch(ctx, results[i].URL)
+			if err != nil {
+				slog.Debug("Failed to fetch page content", "url", results[i].URL, "error", err)
+				return
+			}
+			text = truncate(text, perPage)
+			if text != "" {
+				enriched[i].Content = results[i].Content + "\n\nПолный текст страницы:\n" + text
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return enriched
+}
+
+// Fetch returns the readable text of the page at pageURL, using the cache.
+func (f *Fetcher) Fetch(ctx context.Context, pageURL string) (string, error) {
+	if text, ok := f.cache.Get(pageURL); ok {
+		return text, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	res, err := f.HTTP.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type")); mediaType != "" && mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return "", fmt.Errorf("unsupported content type %q", mediaType)
+	}
+
+	text, err := ExtractText(io.LimitReader(res.Body, maxPageBytes))
+	if err != nil {
+		return "", err
+	}
+
+	f.cache.Set(pageURL, text)
+	return text, nil
+}
+
+// ExtractText strips boilerplate from an HTML document and returns the text
+// of its main content with whitespace collapsed.
+func ExtractText(r io.Reader) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return "", err
+	}
+
+	doc.Find(boilerplateSelectors).Remove()
+
+	content := doc.Find("body")
+	for _, sel := range mainSelectors {
+		// Pick the largest candidate: pages often have several <article> teasers.
+		var best *goquery.Selection
+		bestLen := 0
+		doc.Find(sel).Each(func(_ int, s *goquery.Selection) {
+			if l := len(s.Text()); l > bestLen {
+				best, bestLen = s, l
+			}
+		})
+		if best != nil && bestLen >= 200 {
+			content = best
+			break
+		}
+	}
+
+	// Keep block boundaries so words from adjacent paragraphs don't merge.
+	content.Find("p, div, li, h1, h2, h3, h4, h5, h6, br, tr, section").Each(func(_ int, s *goquery.Selection) {
+		s.AppendHtml("\n")
+	})
+
+	return collapseWhitespace(content.Text()), nil
+}
+
+func collapseWhitespace(s string) string {
+	lines := strings.Split(s, "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// truncate cuts s to at most maxRunes runes, preferring a line boundary.
+func truncate(s string, maxRunes int) string {
+	runes := []rune(s)
+	if len(runes) <= maxRunes {
+		return s
+	}
+	cut := string(runes[:maxRunes])
+	if i := strings.LastIndex(cut, "\n"); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return cut + " …"
+}
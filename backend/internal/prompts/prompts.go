@@ -0,0 +1,187 @@
+// Package prompts renders the LLM prompts from embedded text/template files,
+// so prompt wording lives next to the other configuration instead of in Go
+// string literals.
+//
+// Every template starts with a version comment:
+//
+//	{{- /* version: search-v3 */ -}}
+//
+// Bump it whenever the wording changes. The version is stored on every
+// hackathon extracted with the prompt, so quality regressions can be traced
+// back to a prompt change.
+package prompts
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"hackflow-api/internal/guard"
+	"hackflow-api/internal/region"
+	"hackflow-api/internal/telegram"
+	"hackflow-api/internal/websearch"
+)
+
+// Template names.
+const (
+	SearchTemplate = "search"
+	PostTemplate   = "post"
+	PostsTemplate  = "posts"
+	VerifyTemplate = "verify"
+)
+
+//go:embed templates/*.tmpl
+var files embed.FS
+
+var versionRe = regexp.MustCompile(`^\{\{-?\s*/\*\s*version:\s*(\S+)\s*\*/\s*-?\}\}`)
+
+var (
+	templates = template.Must(template.New("").Funcs(template.FuncMap{
+		"inc":  func(i int) int { return i + 1 },
+		"join": strings.Join,
+		// untrusted escapes text placed inside <untrusted_*> tags
+		"untrusted": guard.Escape,
+	}).ParseFS(files, "templates/*.tmpl"))
+
+	versions = mustReadVersions()
+)
+
+// Prompt is a rendered prompt together with the template version it came from.
+type Prompt struct {
+	Name    string
+	Version string
+	Text    string
+}
+
+// SearchData is the input of the web agent extraction prompt.
+type SearchData struct {
+	Date    string
+	Query   string
+	Results []websearch.Result
+	Locale  region.Locale
+	// Tags are the taxonomy slugs the model may pick from; empty omits the
+	// tags field.
+	Tags []string
+}
+
+// NationalCities is exposed to the template as a field-like method.
+func (d SearchData) NationalCities() []string {
+	return d.Locale.Region.NationalCities(d.Locale.Lang)
+}
+
+// PostData is the input of the Telegram post extraction prompt.
+type PostData struct {
+	Date        string
+	PublishedAt string
+	Text        string
+	// Links are the real links of the post; the model must pick from them.
+	Links  []telegram.Link
+	Locale region.Locale
+	Tags   []string
+}
+
+// PostsData is the input of the batch prompt that extracts several Telegram
+// posts with one model call.
+type PostsData struct {
+	Date   string
+	Posts  []BatchPost
+	Locale region.Locale
+	Tags   []string
+}
+
+// BatchPost is one post of a batch, numbered from 1 in the prompt.
+type BatchPost struct {
+	PublishedAt string
+	Text        string
+	Links       []telegram.Link
+}
+
+// VerifyData is the input of the classifier's yes/no prompt for posts whose
+// keyword score is not conclusive.
+type VerifyData struct {
+	Text string
+}
+
+// Search renders the prompt that extracts hackathons from web search results.
+func Search(data SearchData) (Prompt, error) {
+	return render(SearchTemplate, data)
+}
+
+// Post renders the prompt that extracts a hackathon from a Telegram post.
+func Post(data PostData) (Prompt, error) {
+	return render(PostTemplate, data)
+}
+
+// Posts renders the prompt that extracts one hackathon per Telegram post for a
+// batch of posts.
+func Posts(data PostsData) (Prompt, error) {
+	return render(PostsTemplate, data)
+}
+
+// Verify renders the prompt that asks whether a post announces a hackathon.
+func Verify(data VerifyData) (Prompt, error) {
+	return render(VerifyTemplate, data)
+}
+
+// Versions returns the current version of every template, keyed by name.
+func Versions() map[string]string {
+	out := make(map[string]string, len(versions))
+	for name, v := range versions {
+		out[name] = v
+	}
+	return out
+}
+
+// Names returns the template names in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func render(name string, data any) (Prompt, error) {
+	var b strings.Builder
+	if err := templates.ExecuteTemplate(&b, name+".tmpl", data); err != nil {
+		return Prompt{}, fmt.Errorf("failed to render prompt %s: %w", name, err)
+	}
+	return Prompt{Name: name, Version: versions[name], Text: b.String()}, nil
+}
+
+// mustReadVersions extracts the version comment of every template and panics
+// if one is missing or reused, like template.Must does for syntax errors.
+func mustReadVersions() map[string]string {
+	paths, err := fs.Glob(files, "templates/*.tmpl")
+	if err != nil {
+		panic(err)
+	}
+
+	out := make(map[string]string, len(paths))
+	seen := make(map[string]string, len(paths))
+	for _, p := range paths {
+		data, err := files.ReadFile(p)
+		if err != nil {
+			panic(err)
+		}
+		name := strings.TrimSuffix(path.Base(p), ".tmpl")
+
+		m := versionRe.FindSubmatch(data)
+		if m == nil {
+			panic(fmt.Sprintf("prompts: %s has no version comment", p))
+		}
+		v := string(m[1])
+		if other, dup := seen[v]; dup {
+			panic(fmt.Sprintf("prompts: %s and %s share version %s", other, name, v))
+		}
+		seen[v] = name
+		out[name] = v
+	}
+	return out
+}
@@ -0,0 +1,425 @@
+// Package region describes the audiences a deployment serves: the country the
+// web agent searches in, the language of extracted fields and how city names
+// are normalized.
+package region
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+
+	"hackflow-api/internal/models"
+)
+
+//go:embed regions.json
+var defaultRegions []byte
+
+// ErrUnknown is returned for region or language codes missing from the config.
+var ErrUnknown = errors.New("unknown region or language")
+
+// Language holds the phrases the model must use for a target language. Name
+// is written in the language of the prompt templates, e.g. "английский".
+type Language struct {
+	Name       string `json:"name"`
+	DatesTBD   string `json:"datesTbd"`
+	NoAgeLimit string `json:"noAgeLimit"`
+}
+
+// City is a city with its name in every supported language. Slug is the
+// stable key stored on hackathons (see models.City). National cities are where
+// nationwide events are assumed to take place.
+type City struct {
+	Slug     string            `json:"slug"`
+	Names    map[string]string `json:"names"`
+	Aliases  []string          `json:"aliases"`
+	Lat      float64           `json:"lat"`
+	Lon      float64           `json:"lon"`
+	National bool              `json:"national"`
+	// Region is the code of the region the city belongs to, set on load
+	Region string `json:"-"`
+}
+
+// Region is a country the agent searches in.
+type Region struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Language string `json:"language"`
+	// SearchQuery is a text/template for the web search query; {{.Query}} is
+	// the user's query.
+	SearchQuery string `json:"searchQuery"`
+	// NationalEvents describes how the sources mark nationwide events.
+	NationalEvents string `json:"nationalEvents"`
+	Cities         []City `json:"cities"`
+
+	query *template.Template
+	// cities maps every normalized name and alias to an index in Cities
+	cities map[string]int
+}
+
+// Config is the full region configuration.
+type Config struct {
+	Languages map[string]Language `json:"languages"`
+	Regions   []*Region           `json:"regions"`
+}
+
+// Locale is a region together with the language results are returned in.
+type Locale struct {
+	Region   *Region
+	Lang     string
+	Language Language
+}
+
+// Key identifies the locale in cache keys and logs, e.g. "kz/ru".
+func (l Locale) Key() string {
+	return l.Region.Code + "/" + l.Lang
+}
+
+// City returns the canonical name of a known city of the locale's region in
+// the locale's language together with its slug. Unknown names are returned
+// unchanged with an empty slug.
+func (l Locale) City(name string) (string, string) {
+	city, ok := l.Region.FindCity(name)
+	if !ok {
+		return name, ""
+	}
+	return city.Name(l.Lang), city.Slug
+}
+
+// citySeparators split a free-text list of cities such as "Астана и Алматы"
+// or "Astana / Almaty".
+var citySeparators = strings.NewReplacer(" и ", ",", " and ", ",", " және ", ",", "/", ",", ";", ",", "\n", ",")
+
+// CitySlugs returns the slugs of the known cities of the locale's region among
+// names, in order and without duplicates. Each name may itself be a list
+// ("Астана и Алматы"). National events are also held in the region's national
+// cities.
+func (l Locale) CitySlugs(names []string, national bool) []string {
+	var slugs []string
+	seen := make(map[string]bool)
+	add := func(slug string) {
+		if !seen[slug] {
+			seen[slug] = true
+			slugs = append(slugs, slug)
+		}
+	}
+
+	for _, name := range names {
+		for _, part := range strings.Split(citySeparators.Replace(name), ",") {
+			if city, ok := l.Region.FindCity(part); ok {
+				add(city.Slug)
+			}
+		}
+	}
+	if national {
+		for _, city := range l.Region.Cities {
+			if city.National {
+				add(city.Slug)
+			}
+		}
+	}
+	return slugs
+}
+
+// Registry resolves region and language codes from API requests.
+type Registry struct {
+	cfg     *Config
+	regions map[string]*Region
+	// cities maps every city slug to its city across all regions
+	cities map[string]*City
+	// defaultRegion and defaultLang are used when a request does not specify
+	// them; an empty defaultLang means the region's own language
+	defaultRegion string
+	defaultLang   string
+}
+
+// Load reads a JSON region configuration. An empty path yields the built-in
+// regions. defaultRegion must be one of the configured codes; defaultLang may
+// be empty.
+func Load(path, defaultRegion, defaultLang string) (*Registry, error) {
+	data := defaultRegions
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read regions config: %w", err)
+		}
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse regions config: %w", err)
+	}
+
+	r := &Registry{
+		cfg:           &cfg,
+		regions:       make(map[string]*Region, len(cfg.Regions)),
+		cities:        make(map[string]*City),
+		defaultRegion: defaultRegion,
+		defaultLang:   defaultLang,
+	}
+	for _, reg := range cfg.Regions {
+		if err := r.add(reg); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := r.regions[defaultRegion]; !ok {
+		return nil, fmt.Errorf("default region %q: %w", defaultRegion, ErrUnknown)
+	}
+	if _, ok := cfg.Languages[defaultLang]; defaultLang != "" && !ok {
+		return nil, fmt.Errorf("default language %q: %w", defaultLang, ErrUnknown)
+	}
+	return r, nil
+}
+
+func (r *Registry) add(reg *Region) error {
+	if reg.Code == "" {
+		return errors.New("region without code in regions config")
+	}
+	if _, dup := r.regions[reg.Code]; dup {
+		return fmt.Errorf("region %s: duplicate code", reg.Code)
+	}
+	if _, ok := r.cfg.Languages[reg.Language]; !ok {
+		return fmt.Errorf("region %s: language %q: %w", reg.Code, reg.Language, ErrUnknown)
+	}
+
+	tmpl, err := template.New(reg.Code).Option("missingkey=error").Parse(reg.SearchQuery)
+	if err != nil {
+		return fmt.Errorf("region %s: invalid searchQuery: %w", reg.Code, err)
+	}
+	reg.query = tmpl
+
+	reg.cities = make(map[string]int)
+	for i := range reg.Cities {
+		city := &reg.Cities[i]
+		if city.Slug == "" {
+			return fmt.Errorf("region %s: city %q without slug", reg.Code, city.Name(reg.Language))
+		}
+		if other, dup := r.cities[city.Slug]; dup {
+			return fmt.Errorf("region %s: city slug %q already used in region %s", reg.Code, city.Slug, other.Region)
+		}
+		city.Region = reg.Code
+		r.cities[city.Slug] = city
+
+		reg.cities[city.Slug] = i
+		for _, name := range city.Names {
+			reg.cities[models.NormalizeTitle(name)] = i
+		}
+		for _, alias := range city.Aliases {
+			reg.cities[models.NormalizeTitle(alias)] = i
+		}
+	}
+
+	r.regions[reg.Code] = reg
+	return nil
+}
+
+// Resolve returns the locale for a region and language code. Empty codes
+// fall back to the defaults passed to Load.
+func (r *Registry) Resolve(regionCode, lang string) (Locale, error) {
+	if regionCode == "" {
+		regionCode = r.defaultRegion
+	}
+	reg, ok := r.regions[strings.ToLower(regionCode)]
+	if !ok {
+		return Locale{}, fmt.Errorf("region %q: %w", regionCode, ErrUnknown)
+	}
+
+	if lang == "" {
+		lang = r.defaultLang
+	}
+	if lang == "" {
+		lang = reg.Language
+	}
+	lang = strings.ToLower(lang)
+	language, ok := r.cfg.Languages[lang]
+	if !ok {
+		return Locale{}, fmt.Errorf("language %q: %w", lang, ErrUnknown)
+	}
+
+	return Locale{Region: reg, Lang: lang, Language: language}, nil
+}
+
+// Codes returns the configured region codes in sorted order.
+func (r *Registry) Codes() []string {
+	codes := make([]string, 0, len(r.regions))
+	for code := range r.regions {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Regions returns the configured regions sorted by code.
+func (r *Registry) Regions() []*Region {
+	regions := make([]*Region, 0, len(r.regions))
+	for _, code := range r.Codes() {
+		regions = append(regions, r.regions[code])
+	}
+	return regions
+}
+
+// FindCity looks a city up by slug, name or alias in every region; see
+// Region.FindCity for the accepted spellings.
+func (r *Registry) FindCity(name string) (*City, bool) {
+	if city, ok := r.cities[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return city, true
+	}
+	for _, code := range r.Codes() {
+		if city, ok := r.regions[code].FindCity(name); ok {
+			return city, true
+		}
+	}
+	return nil, false
+}
+
+// CitySlugs is Locale.CitySlugs across all regions: names are looked up in
+// every region, and national events get the national cities of the regions
+// their cities belong to (the default region if none is known).
+func (r *Registry) CitySlugs(names []string, national bool) []string {
+	var slugs []string
+	seen := make(map[string]bool)
+	regions := make(map[string]bool)
+	for _, name := range names {
+		for _, part := range strings.Split(citySeparators.Replace(name), ",") {
+			if city, ok := r.FindCity(part); ok && !seen[city.Slug] {
+				seen[city.Slug] = true
+				regions[city.Region] = true
+				slugs = append(slugs, city.Slug)
+			}
+		}
+	}
+	if !national {
+		return slugs
+	}
+
+	if len(regions) == 0 {
+		regions[r.defaultRegion] = true
+	}
+	for _, code := range r.Codes() {
+		if !regions[code] {
+			continue
+		}
+		for _, city := range r.regions[code].Cities {
+			if city.National && !seen[city.Slug] {
+				seen[city.Slug] = true
+				slugs = append(slugs, city.Slug)
+			}
+		}
+	}
+	return slugs
+}
+
+// WebQuery renders the web search query for the user's query.
+func (reg *Region) WebQuery(query string) (string, error) {
+	var b strings.Builder
+	if err := reg.query.Execute(&b, struct{ Query string }{query}); err != nil {
+		return "", fmt.Errorf("region %s: failed to render search query: %w", reg.Code, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// NormalizeCity returns the canonical name of a known city in lang, e.g.
+// "Nur-Sultan" -> "Астана". Unknown cities are returned unchanged.
+func (reg *Region) NormalizeCity(name, lang string) string {
+	city, ok := reg.FindCity(name)
+	if !ok {
+		return name
+	}
+	return city.Name(lang)
+}
+
+// cityPrefixes and citySuffixes are words around a city name that free-text
+// sources add, e.g. "г. Астана" or "Almaty city"; matched after
+// models.NormalizeTitle.
+var (
+	cityPrefixes = []string{"г ", "гор ", "город ", "city of ", "city ", "қ "}
+	citySuffixes = []string{" city", " қаласы", " қ"}
+)
+
+// FindCity looks a city up by slug, name in any language or alias. Case,
+// punctuation, words like "г." or "city" and a trailing country ("Astana,
+// Kazakhstan") are ignored.
+func (reg *Region) FindCity(name string) (*City, bool) {
+	parts := strings.Split(name, ",")
+	for _, part := range parts {
+		key := models.NormalizeTitle(part)
+		if key == "" {
+			continue
+		}
+		if i, ok := reg.lookup(key); ok {
+			return &reg.Cities[i], true
+		}
+	}
+	return nil, false
+}
+
+func (reg *Region) lookup(key string) (int, bool) {
+	if i, ok := reg.cities[key]; ok {
+		return i, true
+	}
+	for _, prefix := range cityPrefixes {
+		key = strings.TrimPrefix(key, prefix)
+	}
+	for _, suffix := range citySuffixes {
+		key = strings.TrimSuffix(key, suffix)
+	}
+	i, ok := reg.cities[key]
+	return i, ok
+}
+
+// NationalCities lists the cities nationwide events are assumed to be held in.
+func (reg *Region) NationalCities(lang string) []string {
+	var names []string
+	for _, city := range reg.Cities {
+		if city.National {
+			names = append(names, city.Name(lang))
+		}
+	}
+	return names
+}
+
+// CityHints describes how to spell every known city in lang, e.g.
+// "Astana, Nur-Sultan -> Астана", for use in prompts.
+func (reg *Region) CityHints(lang string) []string {
+	hints := make([]string, 0, len(reg.Cities))
+	for _, city := range reg.Cities {
+		target := city.Name(lang)
+
+		var variants []string
+		for _, code := range sortedKeys(city.Names) {
+			if name := city.Names[code]; name != target {
+				variants = append(variants, name)
+			}
+		}
+		variants = append(variants, city.Aliases...)
+		if len(variants) == 0 {
+			continue
+		}
+		hints = append(hints, strings.Join(variants, ", ")+" -> "+target)
+	}
+	return hints
+}
+
+// Name returns the city name in lang, falling back to any available name.
+func (c City) Name(lang string) string {
+	if name, ok := c.Names[lang]; ok {
+		return name
+	}
+	if codes := sortedKeys(c.Names); len(codes) > 0 {
+		return c.Names[codes[0]]
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
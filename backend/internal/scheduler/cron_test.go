@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"a * * * *",
+		"1-x * * * *",
+		"@every",
+	}
+	for _, expr := range tests {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Saturday, 1 March 2025, 10:07
+	from := time.Date(2025, 3, 1, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, 3, 1, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 3, 1, 10, 15, 0, 0, time.UTC)},
+		{"0 */6 * * *", time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{"10-40/10 * * * *", time.Date(2025, 3, 1, 10, 10, 0, 0, time.UTC)},
+		{"5-7 * * * *", time.Date(2025, 3, 1, 11, 5, 0, 0, time.UTC)},
+		{"1,15,30 * * * *", time.Date(2025, 3, 1, 10, 15, 0, 0, time.UTC)},
+		{"0 10 * * *", time.Date(2025, 3, 2, 10, 0, 0, 0, time.UTC)},
+		{"30 8 1,15 * *", time.Date(2025, 3, 15, 8, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Day of week: 1-5 are weekdays, both 0 and 7 are Sunday
+		{"0 9 * * 1-5", time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 0", time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC)},
+		{"0 12 * * 6", time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{"0 9 * * */2", time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC)},
+		// Both day of month and day of week restricted: either one matches
+		{"0 0 15 * 1", time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 2 * 5", time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 3, 1, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Parse(%q).Next(%s) = %s, want %s", tt.expr, from.Format(time.RFC3339), got.Format(time.RFC3339), tt.want.Format(time.RFC3339))
+		}
+	}
+}
+
+func TestScheduleNextNever(t *testing.T) {
+	s, err := Parse("0 0 31 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Next(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("Next = %s, want zero time for a schedule that never fires", got)
+	}
+}
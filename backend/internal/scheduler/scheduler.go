@@ -0,0 +1,219 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownJob is returned when triggering a job that was never registered.
+	ErrUnknownJob = errors.New("unknown job")
+	// ErrAlreadyRunning is returned when a job is triggered while a previous run
+	// of the same job has not finished yet.
+	ErrAlreadyRunning = errors.New("job is already running")
+)
+
+// JobFunc is the unit of work executed by the scheduler.
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	schedule *Schedule
+	run      JobFunc
+	mu       sync.Mutex
+}
+
+// Scheduler runs named jobs on cron schedules. Each job is guarded by its own
+// lock, so a scheduled run and a manual trigger of the same job never overlap.
+type Scheduler struct {
+	jitter time.Duration
+
+	mu   sync.RWMutex
+	jobs map[string]*job
+	wg   sync.WaitGroup
+}
+
+// New creates a Scheduler that delays every scheduled run by a random
+// duration in [0, jitter).
+func New(jitter time.Duration) *Scheduler {
+	return &Scheduler{
+		jitter: jitter,
+		jobs:   make(map[string]*job),
+	}
+}
+
+// Add registers a job under a unique name.
+func (s *Scheduler) Add(name string, schedule *Schedule, fn JobFunc) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %q already registered", name)
+	}
+	s.jobs[name] = &job{name: name, schedule: schedule, run: fn}
+	return nil
+}
+
+// Jobs returns the names of all registered jobs in sorted order.
+func (s *Scheduler) Jobs() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start launches a goroutine per job that waits for the next activation time
+// and runs the job. It returns immediately; cancel ctx to stop scheduling.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go func(j *job) {
+			defer s.wg.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+}
+
+// Wait blocks until all scheduling loops and in-flight runs have returned.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			slog.Warn("Schedule never fires, stopping job", "job", j.name, "schedule", j.schedule.String())
+			return
+		}
+		delay := time.Until(next) + s.randomJitter()
+		slog.Debug("Next run scheduled", "job", j.name, "at", time.Now().Add(delay).Format(time.RFC3339))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := s.runLocked(ctx, j); errors.Is(err, ErrAlreadyRunning) {
+			slog.Warn("Skipping scheduled run, previous run still in progress", "job", j.name)
+		}
+	}
+}
+
+// RunNow runs the named job synchronously, failing with ErrAlreadyRunning if
+// it is currently executing.
+func (s *Scheduler) RunNow(ctx context.Context, name string) error {
+	j, err := s.lookup(name)
+	if err != nil {
+		return err
+	}
+	return s.runLocked(ctx, j)
+}
+
+// Trigger starts the named job in the background. The lock is acquired before
+// returning, so ErrAlreadyRunning is reported to the caller synchronously.
+func (s *Scheduler) Trigger(ctx context.Context, name string) error {
+	j, err := s.lookup(name)
+	if err != nil {
+		return err
+	}
+	if !j.mu.TryLock() {
+		return ErrAlreadyRunning
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer j.mu.Unlock()
+		s.execute(ctx, j)
+	}()
+	return nil
+}
+
+// RunAll runs every registered job once, sequentially, in name order, and
+// returns the errors of the jobs that failed. A job that is already running
+// is skipped; the remaining jobs are not started once ctx is cancelled.
+func (s *Scheduler) RunAll(ctx context.Context) error {
+	var errs []error
+	for _, name := range s.Jobs() {
+		if ctx.Err() != nil {
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+		err := s.RunNow(ctx, name)
+		switch {
+		case errors.Is(err, ErrAlreadyRunning):
+			slog.Warn("Skipping job, already running", "job", name)
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunAllBackground runs RunAll in a goroutine that Wait also waits for, so a
+// shutdown does not cut the initial run short. Job errors are only logged.
+func (s *Scheduler) RunAllBackground(ctx context.Context) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		_ = s.RunAll(ctx)
+	}()
+}
+
+func (s *Scheduler) lookup(name string) (*job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJob, name)
+	}
+	return j, nil
+}
+
+func (s *Scheduler) runLocked(ctx context.Context, j *job) error {
+	if !j.mu.TryLock() {
+		return ErrAlreadyRunning
+	}
+	defer j.mu.Unlock()
+
+	return s.execute(ctx, j)
+}
+
+func (s *Scheduler) execute(ctx context.Context, j *job) error {
+	start := time.Now()
+	slog.Info("Job started", "job", j.name)
+
+	err := j.run(ctx)
+	if err != nil {
+		slog.Error("Job failed", "job", j.name, "duration", time.Since(start), "error", err)
+		return err
+	}
+
+	slog.Info("Job finished", "job", j.name, "duration", time.Since(start))
+	return nil
+}
+
+func (s *Scheduler) randomJitter() time.Duration {
+	if s.jitter <= 0 {
+		return 0
+	}
+	return rand.N(s.jitter)
+}
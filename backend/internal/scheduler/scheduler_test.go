@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockingJob returns a job that signals started and blocks until release
+// is closed.
+func blockingJob(started chan<- struct{}, release <-chan struct{}) JobFunc {
+	return func(ctx context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+}
+
+func newTestScheduler(t *testing.T, jobs map[string]JobFunc) *Scheduler {
+	t.Helper()
+	schedule, err := Parse("0 0 1 1 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(0)
+	for name, fn := range jobs {
+		if err := s.Add(name, schedule, fn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestRunNowRejectsOverlap(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	s := newTestScheduler(t, map[string]JobFunc{"scrape": blockingJob(started, release)})
+	ctx := context.Background()
+
+	done := make(chan error)
+	go func() { done <- s.RunNow(ctx, "scrape") }()
+	<-started
+
+	if err := s.RunNow(ctx, "scrape"); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("overlapping RunNow = %v, want ErrAlreadyRunning", err)
+	}
+	if err := s.Trigger(ctx, "scrape"); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("Trigger during RunNow = %v, want ErrAlreadyRunning", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("RunNow = %v", err)
+	}
+	// The lock is released with the run, so the job can run again.
+	go func() { <-started }()
+	if err := s.RunNow(ctx, "scrape"); err != nil {
+		t.Errorf("RunNow after the previous run = %v", err)
+	}
+}
+
+func TestTriggerRejectsOverlap(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	s := newTestScheduler(t, map[string]JobFunc{"scrape": blockingJob(started, release)})
+	ctx := context.Background()
+
+	if err := s.Trigger(ctx, "scrape"); err != nil {
+		t.Fatalf("Trigger = %v", err)
+	}
+	// Trigger holds the lock before it returns, so no wait for started.
+	if err := s.Trigger(ctx, "scrape"); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("overlapping Trigger = %v, want ErrAlreadyRunning", err)
+	}
+	if err := s.RunNow(ctx, "scrape"); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("RunNow during Trigger = %v, want ErrAlreadyRunning", err)
+	}
+
+	<-started
+	close(release)
+	s.Wait()
+}
+
+func TestLockIsPerJob(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	ran := false
+	s := newTestScheduler(t, map[string]JobFunc{
+		"slow": blockingJob(started, release),
+		"fast": func(context.Context) error { ran = true; return nil },
+	})
+	ctx := context.Background()
+
+	if err := s.Trigger(ctx, "slow"); err != nil {
+		t.Fatalf("Trigger = %v", err)
+	}
+	<-started
+	if err := s.RunNow(ctx, "fast"); err != nil || !ran {
+		t.Errorf("RunNow of another job = %v, ran = %v", err, ran)
+	}
+
+	close(release)
+	s.Wait()
+}
+
+func TestUnknownJob(t *testing.T) {
+	s := newTestScheduler(t, nil)
+	if err := s.RunNow(context.Background(), "missing"); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("RunNow = %v, want ErrUnknownJob", err)
+	}
+	if err := s.Trigger(context.Background(), "missing"); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("Trigger = %v, want ErrUnknownJob", err)
+	}
+}
+
+func TestRunAllJoinsErrors(t *testing.T) {
+	errA, errC := errors.New("a failed"), errors.New("c failed")
+	var order []string
+	job := func(name string, err error) JobFunc {
+		return func(context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+	s := newTestScheduler(t, map[string]JobFunc{
+		"c": job("c", errC),
+		"a": job("a", errA),
+		"b": job("b", nil),
+	})
+
+	err := s.RunAll(context.Background())
+	if !errors.Is(err, errA) || !errors.Is(err, errC) {
+		t.Errorf("RunAll = %v, want both job errors", err)
+	}
+	if got := len(order); got != 3 || order[0] != "a" || order[2] != "c" {
+		t.Errorf("jobs ran in order %v, want [a b c]", order)
+	}
+
+	ok := newTestScheduler(t, map[string]JobFunc{"b": job("b", nil)})
+	if err := ok.RunAll(context.Background()); err != nil {
+		t.Errorf("RunAll without failures = %v", err)
+	}
+}
+
+func TestRunAllStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var ran []string
+	s := newTestScheduler(t, map[string]JobFunc{
+		"a": func(context.Context) error { ran = append(ran, "a"); cancel(); return nil },
+		"b": func(context.Context) error { ran = append(ran, "b"); return nil },
+	})
+
+	done := make(chan error)
+	go func() { done <- s.RunAll(ctx) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("RunAll = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("RunAll did not return after cancel")
+	}
+	if len(ran) != 1 {
+		t.Errorf("jobs ran = %v, want only a", ran)
+	}
+}
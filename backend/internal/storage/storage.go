@@ -0,0 +1,17 @@
+// Package storage provides a minimal blob store abstraction for media files.
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// BlobStore stores binary objects under string keys and exposes them via URL.
+type BlobStore interface {
+	// Put writes the object and returns its public URL.
+	Put(ctx context.Context, key string, r io.Reader, contentType string) (string, error)
+	// Exists reports whether an object with the key is already stored.
+	Exists(ctx context.Context, key string) (bool, error)
+	// URL returns the public URL of a key without checking that it exists.
+	URL(key string) string
+}
@@ -0,0 +1,218 @@
+// Package taxonomy is the controlled list of topic tags hackathons are
+// labelled with ("ai-ml", "fintech", "gamedev"). Tags are picked by the model
+// from this list and complemented by keyword matching on the title and
+// tracks, so filtering by a tag does not depend on how an announcement
+// phrased its topic.
+package taxonomy
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"hackflow-api/internal/models"
+)
+
+//go:embed tags.json
+var defaultTags []byte
+
+// Tag is a topic of the taxonomy. Slug is the stable key stored on
+// hackathons (see models.Tag).
+type Tag struct {
+	Slug  string            `json:"slug"`
+	Names map[string]string `json:"names"`
+	// Keywords are words or phrases that assign the tag when they occur in a
+	// title or track. A trailing "*" on a word matches any ending
+	// ("нейросет*" matches "нейросети"); other words must match whole.
+	Keywords []string `json:"keywords"`
+
+	patterns []*regexp.Regexp
+}
+
+// Name returns the tag name in lang, falling back to English and the slug.
+func (t Tag) Name(lang string) string {
+	if name, ok := t.Names[lang]; ok {
+		return name
+	}
+	if name, ok := t.Names["en"]; ok {
+		return name
+	}
+	return t.Slug
+}
+
+// Config is the JSON layout of the taxonomy file.
+type Config struct {
+	Tags []Tag `json:"tags"`
+}
+
+// Taxonomy is a loaded tag list. A nil Taxonomy has no tags, so callers can
+// treat tagging as optional.
+type Taxonomy struct {
+	tags []Tag
+	// byName maps every slug and normalized name to an index in tags
+	byName map[string]int
+}
+
+// Load reads a JSON taxonomy. An empty path yields the built-in tags.
+func Load(path string) (*Taxonomy, error) {
+	data := defaultTags
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read tags config: %w", err)
+		}
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse tags config: %w", err)
+	}
+
+	t := &Taxonomy{tags: cfg.Tags, byName: make(map[string]int)}
+	for i := range t.tags {
+		tag := &t.tags[i]
+		if tag.Slug == "" {
+			return nil, errors.New("tag without slug in tags config")
+		}
+		if _, dup := t.byName[tag.Slug]; dup {
+			return nil, fmt.Errorf("tag %s: duplicate slug", tag.Slug)
+		}
+		t.byName[tag.Slug] = i
+		for _, name := range tag.Names {
+			if key := models.NormalizeTitle(name); key != "" {
+				if _, taken := t.byName[key]; !taken {
+					t.byName[key] = i
+				}
+			}
+		}
+		for _, kw := range tag.Keywords {
+			re, err := keywordPattern(kw)
+			if err != nil {
+				return nil, fmt.Errorf("tag %s: keyword %q: %w", tag.Slug, kw, err)
+			}
+			tag.patterns = append(tag.patterns, re)
+		}
+	}
+	return t, nil
+}
+
+// keywordPattern compiles a keyword into a regexp over text normalized with
+// models.NormalizeTitle, where words are separated by single spaces.
+func keywordPattern(kw string) (*regexp.Regexp, error) {
+	var parts []string
+	prefix := false
+	for _, word := range strings.Fields(kw) {
+		prefix = strings.HasSuffix(word, "*")
+		norm := models.NormalizeTitle(strings.TrimSuffix(word, "*"))
+		if norm == "" {
+			continue
+		}
+		part := regexp.QuoteMeta(norm)
+		if prefix {
+			part += `\S*`
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		return nil, errors.New("empty keyword")
+	}
+	return regexp.Compile(`(?:^| )` + strings.Join(parts, " ") + `(?: |$)`)
+}
+
+// Tags returns the tags in configuration order.
+func (t *Taxonomy) Tags() []Tag {
+	if t == nil {
+		return nil
+	}
+	return t.tags
+}
+
+// Slugs returns the slugs of all tags in configuration order.
+func (t *Taxonomy) Slugs() []string {
+	if t == nil {
+		return nil
+	}
+	slugs := make([]string, len(t.tags))
+	for i, tag := range t.tags {
+		slugs[i] = tag.Slug
+	}
+	return slugs
+}
+
+// Find returns the tag with this slug or name in any language ("ai-ml",
+// "AI/ML", "Финтех").
+func (t *Taxonomy) Find(name string) (Tag, bool) {
+	if t == nil {
+		return Tag{}, false
+	}
+	name = strings.TrimSpace(name)
+	if i, ok := t.byName[strings.ToLower(name)]; ok {
+		return t.tags[i], true
+	}
+	if i, ok := t.byName[models.NormalizeTitle(name)]; ok {
+		return t.tags[i], true
+	}
+	return Tag{}, false
+}
+
+// Match returns the slugs of the tags whose keywords occur in any of texts.
+func (t *Taxonomy) Match(texts ...string) []string {
+	if t == nil {
+		return nil
+	}
+	norm := make([]string, 0, len(texts))
+	for _, text := range texts {
+		if n := models.NormalizeTitle(text); n != "" {
+			norm = append(norm, n)
+		}
+	}
+
+	var slugs []string
+	for _, tag := range t.tags {
+		if tag.matches(norm) {
+			slugs = append(slugs, tag.Slug)
+		}
+	}
+	return slugs
+}
+
+func (tag Tag) matches(texts []string) bool {
+	for _, re := range tag.patterns {
+		for _, text := range texts {
+			if re.MatchString(text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Assign combines the tags chosen by the model (slugs or names, unknown ones
+// are dropped) with the tags matched in texts. The result follows
+// configuration order and has no duplicates.
+func (t *Taxonomy) Assign(chosen []string, texts ...string) []string {
+	if t == nil {
+		return nil
+	}
+	picked := make(map[string]bool)
+	for _, name := range chosen {
+		if tag, ok := t.Find(name); ok {
+			picked[tag.Slug] = true
+		}
+	}
+	for _, slug := range t.Match(texts...) {
+		picked[slug] = true
+	}
+
+	var slugs []string
+	for _, tag := range t.tags {
+		if picked[tag.Slug] {
+			slugs = append(slugs, tag.Slug)
+		}
+	}
+	return slugs
+}
@@ -0,0 +1,174 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// shortenerHosts are URL shorteners whose target is resolved before scoring.
+var shortenerHosts = map[string]bool{
+	"bit.ly": true, "clck.ru": true, "tinyurl.com": true, "cutt.ly": true,
+	"t.ly": true, "vk.cc": true, "goo.su": true, "is.gd": true,
+	"rebrand.ly": true, "shorturl.at": true, "u.to": true, "t.co": true,
+}
+
+// registrationHosts are services commonly used for registration forms.
+// A host matches if it equals an entry or is its subdomain; entries with a
+// path must also match the path prefix.
+var registrationHosts = []string{
+	"forms.gle",
+	"docs.google.com/forms",
+	"forms.yandex.ru", "forms.yandex.kz",
+	"typeform.com",
+	"tally.so",
+	"airtable.com",
+	"jotform.com",
+	"forms.office.com",
+	"devpost.com",
+	"timepad.ru",
+	"leader-id.ru",
+	"lu.ma",
+	"eventbrite.com",
+	"ticketon.kz",
+	"registration.",
+}
+
+// registrationWords mark links whose anchor text or URL talks about signing up.
+var registrationWords = []string{
+	"регистрац", "зарегистр", "заявк", "участвовать", "участие",
+	"тіркел", "өтінім",
+	"register", "registration", "sign up", "signup", "apply",
+}
+
+// noiseHosts rarely point to a registration page.
+var noiseHosts = []string{
+	"instagram.com", "youtube.com", "youtu.be", "facebook.com",
+	"vk.com", "linkedin.com", "twitter.com", "x.com", "tiktok.com",
+}
+
+// ResolveRedirects replaces shortened URLs with their final targets. Links
+// that fail to resolve are kept as they are. Telegram's own wrappers
+// (t.me/iv, t.me/share/url) are unwrapped without a request, both in the post
+// and behind a shortener.
+func (c *Client) ResolveRedirects(ctx context.Context, links []Link) []Link {
+	// Follow hops manually to see each Location and stop at the first
+	// non-shortener host.
+	client := *c.HTTP
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resolved := make([]Link, len(links))
+	for i, link := range links {
+		resolved[i] = link
+		if target, err := resolve(ctx, &client, link.URL); err == nil {
+			if target = normalizeHref(target); target != "" {
+				resolved[i].URL = target
+			}
+		}
+	}
+	return resolved
+}
+
+func resolve(ctx context.Context, client *http.Client, rawURL string) (string, error) {
+	current := rawURL
+	for hop := 0; hop < 5; hop++ {
+		u, err := url.Parse(current)
+		if err != nil {
+			return "", err
+		}
+		if !shortenerHosts[strings.TrimPrefix(strings.ToLower(u.Host), "www.")] {
+			return current, nil
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, current, nil)
+		if err != nil {
+			return "", err
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		res.Body.Close()
+
+		location := res.Header.Get("Location")
+		if location == "" {
+			return current, nil
+		}
+		next, err := u.Parse(location)
+		if err != nil {
+			return "", err
+		}
+		current = next.String()
+	}
+	return "", errors.New("too many redirects")
+}
+
+// PickRegistrationLink chooses the most likely registration URL among the
+// post links using deterministic rules. It returns false if no link looks
+// like a registration page, leaving the choice to the LLM.
+func PickRegistrationLink(links []Link) (string, bool) {
+	best, bestScore := "", 0
+	for _, link := range links {
+		if link.Kind == LinkPermalink {
+			continue
+		}
+		if score := scoreLink(link); score > bestScore {
+			best, bestScore = link.URL, score
+		}
+	}
+	return best, bestScore >= 2
+}
+
+func scoreLink(link Link) int {
+	u, err := url.Parse(link.URL)
+	if err != nil {
+		return 0
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	hostPath := host + strings.ToLower(u.Path)
+
+	score := 0
+	for _, pattern := range registrationHosts {
+		if matchHost(host, hostPath, pattern) {
+			score += 3
+			break
+		}
+	}
+
+	haystack := strings.ToLower(link.Text + " " + link.URL)
+	for _, word := range registrationWords {
+		if strings.Contains(haystack, word) {
+			score += 2
+			break
+		}
+	}
+
+	if link.Kind == LinkButton {
+		score++
+	}
+	if isTelegramHost(host) {
+		score -= 3
+	}
+	for _, noise := range noiseHosts {
+		if host == noise || strings.HasSuffix(host, "."+noise) {
+			score -= 2
+			break
+		}
+	}
+
+	return score
+}
+
+func matchHost(host, hostPath, pattern string) bool {
+	if strings.HasSuffix(pattern, ".") {
+		return strings.HasPrefix(host, pattern)
+	}
+	if strings.Contains(pattern, "/") {
+		return strings.HasPrefix(hostPath, pattern)
+	}
+	return host == pattern || strings.HasSuffix(host, "."+pattern)
+}
@@ -0,0 +1,150 @@
+package telegram
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeHref(t *testing.T) {
+	tests := []struct {
+		href, want string
+	}{
+		{"https://forms.gle/abc", "https://forms.gle/abc"},
+		{"  https://astanahub.com/ai  ", "https://astanahub.com/ai"},
+		{"mailto:info@astanahub.com", ""},
+		{"tg://resolve?domain=astanahub", ""},
+		{"?q=%23hackathon", ""}, // relative hashtag search
+		{"https://t.me/s/astanahub?q=%23hackathon", ""},
+		{"https://t.me/s/astanahub/1150", "https://t.me/astanahub/1150"},
+		{"https://t.me/s/astanahub", "https://t.me/s/astanahub"}, // channel preview, not a post
+		{"https://t.me/iv?url=https%3A%2F%2Fastanahub.com%2Fai&rhash=1f", "https://astanahub.com/ai"},
+		{"https://t.me/share/url?url=https%3A%2F%2Flu.ma%2Fhack&text=join", "https://lu.ma/hack"},
+		{"https://telegram.me/share?url=https%3A%2F%2Flu.ma%2Fhack", "https://lu.ma/hack"},
+		{"https://t.me/iv?url=javascript%3Aalert(1)", ""}, // unwrapped target is checked too
+		{"https://t.me/iv", "https://t.me/iv"},
+	}
+	for _, tt := range tests {
+		if got := normalizeHref(tt.href); got != tt.want {
+			t.Errorf("normalizeHref(%q) = %q, want %q", tt.href, got, tt.want)
+		}
+	}
+}
+
+func TestScoreLink(t *testing.T) {
+	tests := []struct {
+		link Link
+		want int
+	}{
+		{Link{URL: "https://forms.gle/abc"}, 3},
+		{Link{URL: "https://docs.google.com/forms/d/1/viewform"}, 3},
+		{Link{URL: "https://docs.google.com/document/d/1"}, 0}, // path must match
+		{Link{URL: "https://events.lu.ma/hack"}, 3},            // subdomain
+		{Link{URL: "https://registration.astanahub.com"}, 5},   // host prefix and the word itself
+		{Link{URL: "https://forms.gle/abc", Text: "Регистрация", Kind: LinkButton}, 6},
+		{Link{URL: "https://astanahub.com/hack", Text: "Подать заявку"}, 2},
+		{Link{URL: "https://astanahub.com/hack", Kind: LinkButton}, 1},
+		{Link{URL: "https://t.me/astanahub_bot", Text: "Регистрация"}, -1},
+		{Link{URL: "https://www.instagram.com/astanahub", Text: "участвовать"}, 0},
+		{Link{URL: "https://notforms.gle/abc"}, 0},
+	}
+	for _, tt := range tests {
+		if got := scoreLink(tt.link); got != tt.want {
+			t.Errorf("scoreLink(%+v) = %d, want %d", tt.link, got, tt.want)
+		}
+	}
+}
+
+func TestPickRegistrationLink(t *testing.T) {
+	tests := []struct {
+		name  string
+		links []Link
+		want  string
+		ok    bool
+	}{
+		{"form wins over site", []Link{
+			{URL: "https://astanahub.com", Text: "сайт"},
+			{URL: "https://forms.gle/abc", Text: "форма"},
+		}, "https://forms.gle/abc", true},
+		{"first of equal scores", []Link{
+			{URL: "https://forms.gle/a"},
+			{URL: "https://tally.so/b"},
+		}, "https://forms.gle/a", true},
+		{"permalinks are ignored", []Link{
+			{URL: "https://t.me/astanahub/1/registration", Kind: LinkPermalink},
+		}, "", false},
+		{"a button alone is not enough", []Link{
+			{URL: "https://astanahub.com", Kind: LinkButton},
+		}, "https://astanahub.com", false},
+		{"no links", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PickRegistrationLink(tt.links)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("PickRegistrationLink = %q, %v; want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// hostTransport sends every request to srv, keeping the original host in the
+// URL path, so the shortener hosts resolve against a local server.
+type hostTransport struct{ srv *httptest.Server }
+
+func (t hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, _ := url.Parse(t.srv.URL)
+	r := req.Clone(req.Context())
+	r.URL.Path = "/" + req.URL.Host + req.URL.Path
+	r.URL.Scheme, r.URL.Host = u.Scheme, u.Host
+	return t.srv.Client().Transport.RoundTrip(r)
+}
+
+func TestResolveRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bit.ly/form":
+			w.Header().Set("Location", "https://forms.gle/abc")
+		case "/bit.ly/chain":
+			w.Header().Set("Location", "https://clck.ru/next")
+		case "/clck.ru/next":
+			w.Header().Set("Location", "/final") // relative to clck.ru
+		case "/clck.ru/final":
+			w.Header().Set("Location", "https://astanahub.com/ai")
+		case "/bit.ly/iv":
+			w.Header().Set("Location", "https://t.me/iv?url=https%3A%2F%2Flu.ma%2Fhack")
+		case "/bit.ly/loop":
+			w.Header().Set("Location", "https://bit.ly/loop")
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusMovedPermanently)
+	}))
+	defer srv.Close()
+
+	c := NewClient(&http.Client{Transport: hostTransport{srv}}, "https://t.me")
+	links := []Link{
+		{URL: "https://bit.ly/form", Text: "форма"},
+		{URL: "https://bit.ly/chain"},
+		{URL: "https://bit.ly/iv"},
+		{URL: "https://bit.ly/loop"},
+		{URL: "https://bit.ly/missing"},
+		{URL: "https://astanahub.com/page"},
+	}
+	got := c.ResolveRedirects(context.Background(), links)
+	want := []Link{
+		{URL: "https://forms.gle/abc", Text: "форма"},
+		{URL: "https://astanahub.com/ai"},
+		{URL: "https://lu.ma/hack"},         // t.me wrapper behind a shortener
+		{URL: "https://bit.ly/loop"},        // too many redirects: kept as is
+		{URL: "https://bit.ly/missing"},     // no Location: kept as is
+		{URL: "https://astanahub.com/page"}, // not a shortener: no request
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ResolveRedirects\n got %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+// Package telegram scrapes public channel posts from the Telegram web preview
+// (https://t.me/s/<channel>).
+package telegram
+
+import "time"
+
+// LinkKind tells where in a post a link was found.
+type LinkKind string
+
+const (
+	LinkText      LinkKind = "text"
+	LinkButton    LinkKind = "button"
+	LinkPreview   LinkKind = "preview"
+	LinkPermalink LinkKind = "permalink"
+)
+
+// Link is a hyperlink extracted from a post.
+type Link struct {
+	URL  string
+	Text string
+	Kind LinkKind
+}
+
+// Post is a single channel message.
+type Post struct {
+	Channel     string
+	Text        string
+	PublishedAt time.Time
+	// Permalink is the public URL of the post itself (https://t.me/<channel>/<id>).
+	Permalink string
+	// Links holds every outbound link of the post, in document order.
+	Links []Link
+	// Images holds the URLs of attached photos (usually the event poster first).
+	Images []string
+}
@@ -0,0 +1,181 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Client fetches channel pages from the Telegram web preview.
+type Client struct {
+	HTTP    *http.Client
+	BaseURL string
+}
+
+// NewClient creates a Client; baseURL is normally "https://t.me".
+func NewClient(httpClient *http.Client, baseURL string) *Client {
+	return &Client{HTTP: httpClient, BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// FetchChannel downloads the web preview of a channel and parses its posts.
+func (c *Client) FetchChannel(ctx context.Context, channel string) ([]Post, error) {
+	pageURL := fmt.Sprintf("%s/s/%s", c.BaseURL, channel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	return ParseChannelPage(res.Body, channel)
+}
+
+// ParseChannelPage extracts posts with their text, publication time and links
+// from a channel web preview page. Posts without text or a valid <time> tag
+// are skipped.
+func ParseChannelPage(r io.Reader, channel string) ([]Post, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var posts []Post
+	doc.Find(".tgme_widget_message").Each(func(i int, s *goquery.Selection) {
+		textSelection := s.Find(".tgme_widget_message_text")
+		if textSelection.Length() == 0 {
+			return
+		}
+
+		// Dates come from the HTML, not from the model.
+		timeAttr, exists := s.Find("time").Attr("datetime")
+		if !exists {
+			return
+		}
+		publishedAt, err := time.Parse(time.RFC3339, timeAttr)
+		if err != nil {
+			return
+		}
+
+		posts = append(posts, Post{
+			Channel:     channel,
+			Text:        strings.TrimSpace(textSelection.Text()),
+			PublishedAt: publishedAt,
+			Permalink:   permalink(s),
+			Links:       extractLinks(s, textSelection),
+			Images:      extractImages(s),
+		})
+	})
+
+	return posts, nil
+}
+
+func permalink(s *goquery.Selection) string {
+	if href, ok := s.Find("a.tgme_widget_message_date").Attr("href"); ok && href != "" {
+		return href
+	}
+	// data-post has the form "<channel>/<id>"
+	if dataPost, ok := s.Attr("data-post"); ok && dataPost != "" {
+		return "https://t.me/" + dataPost
+	}
+	return ""
+}
+
+// backgroundImageRe matches the inline style Telegram uses for photos:
+// background-image:url('https://cdn4.telesco.pe/file/...')
+var backgroundImageRe = regexp.MustCompile(`background-image:\s*url\(['"]?([^'")]+)['"]?\)`)
+
+func extractImages(s *goquery.Selection) []string {
+	var images []string
+	s.Find(".tgme_widget_message_photo_wrap").Each(func(_ int, photo *goquery.Selection) {
+		style, _ := photo.Attr("style")
+		if m := backgroundImageRe.FindStringSubmatch(style); m != nil {
+			if u, err := url.Parse(m[1]); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+				images = append(images, u.String())
+			}
+		}
+	})
+	return images
+}
+
+func extractLinks(s, text *goquery.Selection) []Link {
+	var links []Link
+	seen := make(map[string]bool)
+
+	add := func(href, label string, kind LinkKind) {
+		href = normalizeHref(href)
+		if href == "" || seen[href] {
+			return
+		}
+		seen[href] = true
+		links = append(links, Link{URL: href, Text: strings.TrimSpace(label), Kind: kind})
+	}
+
+	text.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		add(href, a.Text(), LinkText)
+	})
+	s.Find("a.tgme_widget_message_inline_button[href], .tgme_widget_message_inline_row a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		add(href, a.Text(), LinkButton)
+	})
+	s.Find("a.tgme_widget_message_link_preview[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		add(href, a.Find(".link_preview_title").Text(), LinkPreview)
+	})
+
+	return links
+}
+
+// telegramRedirectPaths are t.me paths that wrap another URL in their url
+// parameter: instant view and share links.
+var telegramRedirectPaths = map[string]bool{
+	"/iv":        true,
+	"/share":     true,
+	"/share/url": true,
+}
+
+// normalizeHref drops non-HTTP links and in-channel hashtag searches, unwraps
+// Telegram's redirect wrappers (t.me/iv?url=..., t.me/share/url?url=...) and
+// turns web previews of posts (t.me/s/channel/123) into their permalinks.
+func normalizeHref(href string) string {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+
+	if isTelegramHost(u.Host) {
+		if strings.HasPrefix(u.Path, "/s/") && u.Query().Has("q") {
+			return ""
+		}
+		if telegramRedirectPaths[strings.TrimSuffix(u.Path, "/")] {
+			if target := u.Query().Get("url"); target != "" {
+				return normalizeHref(target)
+			}
+		}
+		if rest, ok := strings.CutPrefix(u.Path, "/s/"); ok && strings.Contains(rest, "/") {
+			u.Path = "/" + rest
+		}
+	}
+
+	return u.String()
+}
+
+func isTelegramHost(host string) bool {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	return host == "t.me" || host == "telegram.me" || host == "telegram.dog"
+}
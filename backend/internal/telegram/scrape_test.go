@@ -0,0 +1,92 @@
+package telegram
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseChannelPage(t *testing.T) {
+	f, err := os.Open("testdata/channel.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	posts, err := ParseChannelPage(f, "astanahub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The photo-only post and the post without <time> are skipped.
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, want 2: %+v", len(posts), posts)
+	}
+
+	hack := posts[0]
+	if hack.Channel != "astanahub" || hack.Permalink != "https://t.me/astanahub/1201" {
+		t.Errorf("post 1: channel %q, permalink %q", hack.Channel, hack.Permalink)
+	}
+	if want := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC); !hack.PublishedAt.Equal(want) {
+		t.Errorf("post 1: published at %v, want %v", hack.PublishedAt, want)
+	}
+	if !strings.HasPrefix(hack.Text, "🚀 Хакатон AI Challenge 2025!") || !strings.Contains(hack.Text, "Регистрация до 15 марта") {
+		t.Errorf("post 1: text %q", hack.Text)
+	}
+	wantLinks := []Link{
+		{URL: "https://forms.gle/abc123", Text: "регистрация", Kind: LinkText},
+		{URL: "https://astanahub.com/ai-challenge", Text: "astanahub.com", Kind: LinkText},
+		{URL: "https://instagram.com/astanahub", Text: "instagram", Kind: LinkText},
+		// the first button repeats a text link and is dropped as a duplicate
+		{URL: "https://astanahub.com/apply", Text: "Подать заявку", Kind: LinkButton},
+	}
+	if !slices.Equal(hack.Links, wantLinks) {
+		t.Errorf("post 1: links\n got %+v\nwant %+v", hack.Links, wantLinks)
+	}
+	if !slices.Equal(hack.Images, []string{"https://cdn4.telesco.pe/file/poster1.jpg"}) {
+		t.Errorf("post 1: images %v", hack.Images)
+	}
+
+	meetup := posts[1]
+	if meetup.Permalink != "https://t.me/astanahub/1202" {
+		t.Errorf("post 2: permalink %q, want the data-post fallback", meetup.Permalink)
+	}
+	wantLinks = []Link{
+		{URL: "https://t.me/astanahub/1150", Text: "выше", Kind: LinkText},
+		{URL: "https://lu.ma/meetup", Text: "Astana Go Meetup", Kind: LinkPreview},
+	}
+	if !slices.Equal(meetup.Links, wantLinks) {
+		t.Errorf("post 2: links\n got %+v\nwant %+v", meetup.Links, wantLinks)
+	}
+	if len(meetup.Images) != 0 {
+		t.Errorf("post 2: images %v, want none", meetup.Images)
+	}
+}
+
+func TestFetchChannel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/s/astanahub" {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, "testdata/channel.html")
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), srv.URL+"/")
+	posts, err := c.FetchChannel(context.Background(), "astanahub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 2 {
+		t.Errorf("got %d posts, want 2", len(posts))
+	}
+
+	if _, err := c.FetchChannel(context.Background(), "missing"); err == nil {
+		t.Error("FetchChannel of a missing channel succeeded, want a status error")
+	}
+}
@@ -0,0 +1,62 @@
+package websearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SearXNG queries a self-hosted SearXNG metasearch instance. The instance
+// must have the JSON output format enabled (search.formats in settings.yml).
+type SearXNG struct {
+	HTTP    *http.Client
+	BaseURL string
+}
+
+// NewSearXNG creates a SearXNG searcher for the instance at baseURL.
+func NewSearXNG(httpClient *http.Client, baseURL string) *SearXNG {
+	return &SearXNG{HTTP: httpClient, BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+type searxngResponse struct {
+	Results []Result `json:"results"`
+}
+
+func (s *SearXNG) Name() string { return "searxng" }
+
+func (s *SearXNG) Search(ctx context.Context, query string, maxResults int) ([]Result, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.BaseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &StatusError{Provider: s.Name(), Code: resp.StatusCode, Body: string(b)}
+	}
+
+	var parsed searxngResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("searxng: failed to decode response: %w", err)
+	}
+
+	results := parsed.Results
+	if maxResults > 0 && len(results) > maxResults {
+		results = results[:maxResults]
+	}
+	return results, nil
+}
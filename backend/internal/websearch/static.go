@@ -0,0 +1,38 @@
+package websearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Static serves results from a JSON fixture file (an array of Result) and
+// never touches the network. It is meant for development and tests.
+type Static struct {
+	Results []Result
+}
+
+// NewStatic loads the fixture at path.
+func NewStatic(path string) (*Static, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("static search: %w", err)
+	}
+
+	var results []Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("static search: failed to parse %s: %w", path, err)
+	}
+	return &Static{Results: results}, nil
+}
+
+func (s *Static) Name() string { return "static" }
+
+func (s *Static) Search(ctx context.Context, query string, maxResults int) ([]Result, error) {
+	results := s.Results
+	if maxResults > 0 && len(results) > maxResults {
+		results = results[:maxResults]
+	}
+	return results, nil
+}
@@ -0,0 +1,79 @@
+package websearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Tavily queries the Tavily Search API (https://tavily.com).
+type Tavily struct {
+	HTTP    *http.Client
+	BaseURL string
+	APIKey  string
+}
+
+// NewTavily creates a Tavily searcher; baseURL is normally "https://api.tavily.com".
+func NewTavily(httpClient *http.Client, baseURL, apiKey string) *Tavily {
+	return &Tavily{HTTP: httpClient, BaseURL: strings.TrimSuffix(baseURL, "/"), APIKey: apiKey}
+}
+
+// TavilyRequest описывает тело запроса к API Tavily
+type TavilyRequest struct {
+	APIKey        string `json:"api_key"`
+	Query         string `json:"query"`
+	SearchDepth   string `json:"search_depth"`
+	IncludeAnswer bool   `json:"include_answer"`
+	MaxResults    int    `json:"max_results"`
+}
+
+// TavilyResponse описывает ответ от API Tavily
+type TavilyResponse struct {
+	Results []Result `json:"results"`
+}
+
+func (t *Tavily) Name() string { return "tavily" }
+
+func (t *Tavily) Search(ctx context.Context, query string, maxResults int) ([]Result, error) {
+	if t.APIKey == "" {
+		return nil, fmt.Errorf("tavily: %w: missing TAVILY_API_KEY", ErrNotConfigured)
+	}
+
+	body, err := json.Marshal(TavilyRequest{
+		APIKey:        t.APIKey,
+		Query:         query,
+		SearchDepth:   "advanced",
+		IncludeAnswer: false,
+		MaxResults:    maxResults,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.BaseURL+"/search", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &StatusError{Provider: t.Name(), Code: resp.StatusCode, Body: string(b)}
+	}
+
+	var parsed TavilyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("tavily: failed to decode response: %w", err)
+	}
+	return parsed.Results, nil
+}
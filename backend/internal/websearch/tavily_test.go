@@ -0,0 +1,38 @@
+package websearch
+
+import (
+	"context"
+	"testing"
+
+	"hackflow-api/internal/config"
+	"hackflow-api/internal/httpclient"
+)
+
+// The cassette in testdata/cassettes was recorded with HTTP_RECORD_MODE=record;
+// the API key in the request body is redacted, so any key replays it.
+func TestTavilyReplay(t *testing.T) {
+	client, err := httpclient.NewFromConfig(&config.Config{
+		HTTPRecordMode:  "replay",
+		HTTPCassetteDir: "testdata/cassettes",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tv := NewTavily(client, "https://api.tavily.com", "tvly-any-key")
+	results, err := tv.Search(context.Background(), "хакатон Алматы 2025", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if got, want := results[0].URL, "https://astanahub.com/ru/event/ai-hackathon-2025"; got != want {
+		t.Errorf("results[0].URL = %q, want %q", got, want)
+	}
+
+	// A query without a cassette must fail instead of reaching the network.
+	if _, err := tv.Search(context.Background(), "другой запрос", 5); err == nil {
+		t.Error("Search without a cassette succeeded, want error")
+	}
+}
@@ -0,0 +1,56 @@
+// Package websearch abstracts the web search providers used by the AI search
+// agent, so the agent can run against Tavily, a self-hosted SearXNG instance
+// or a static fixture in development and tests.
+package websearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"hackflow-api/internal/config"
+)
+
+// ErrNotConfigured is returned when a provider lacks required settings
+// (e.g. an API key).
+var ErrNotConfigured = errors.New("web search provider is not configured")
+
+// Result is a single web search hit.
+type Result struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Content string `json:"content"`
+}
+
+// WebSearcher finds web pages relevant to a query.
+type WebSearcher interface {
+	Search(ctx context.Context, query string, maxResults int) ([]Result, error)
+	// Name identifies the provider in logs.
+	Name() string
+}
+
+// StatusError reports a non-200 response from a provider.
+type StatusError struct {
+	Provider string
+	Code     int
+	Body     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d", e.Provider, e.Code)
+}
+
+// New selects the provider configured by WEB_SEARCH_PROVIDER.
+func New(cfg *config.Config, httpClient *http.Client) (WebSearcher, error) {
+	switch cfg.WebSearchProvider {
+	case "", "tavily":
+		return NewTavily(httpClient, cfg.TavilyBaseURL, cfg.TavilyAPIKey), nil
+	case "searxng":
+		return NewSearXNG(httpClient, cfg.SearXNGBaseURL), nil
+	case "static":
+		return NewStatic(cfg.WebSearchFixture)
+	default:
+		return nil, fmt.Errorf("unknown WEB_SEARCH_PROVIDER %q (expected tavily, searxng or static)", cfg.WebSearchProvider)
+	}
+}
@@ -7,13 +7,37 @@ interface Hackathon {
   id: string;
   title: string;
   date: string;
-  format: string;
+  format: 'offline' | 'online' | 'hybrid' | '';
   city: string;
-  ageLimit: string;
+  minAge: number | null;
+  maxAge: number | null;
+  audiences: ('school' | 'students' | 'professionals')[] | null;
   link: string;
   status: string;
 }
 
+const formatLabels: Record<string, string> = {
+  offline: 'Офлайн',
+  online: 'Онлайн',
+  hybrid: 'Офлайн/Онлайн',
+};
+
+const audienceLabels: Record<string, string> = {
+  school: 'школьники',
+  students: 'студенты',
+  professionals: 'специалисты',
+};
+
+// Текст ограничений по возрасту и аудитории, например «16+, студенты»
+function ageLabel(h: Hackathon): string {
+  const parts: string[] = [];
+  if (h.minAge != null && h.maxAge != null) parts.push(`${h.minAge}–${h.maxAge} лет`);
+  else if (h.minAge != null) parts.push(`${h.minAge}+`);
+  else if (h.maxAge != null) parts.push(`до ${h.maxAge} лет`);
+  for (const a of h.audiences ?? []) parts.push(audienceLabels[a]);
+  return parts.length > 0 ? parts.join(', ') : 'Нет ограничений';
+}
+
 const loadingMessages = [
   "Гуглим интернет...",
   "Читаем сайты...",
@@ -198,12 +222,12 @@ export default function Home() {
                     {/* Format and Date */}
                     <div className="flex flex-wrap gap-2 items-center mb-6">
                       <span className={`px-2.5 py-1 text-xs font-bold uppercase tracking-wider rounded-md border backdrop-blur-sm
-                        ${h.format.includes('Онлайн')
+                        ${h.format === 'online' || h.format === 'hybrid'
                           ? 'bg-blue-500/10 text-blue-400 border-blue-500/20'
                           : 'bg-purple-500/10 text-purple-400 border-purple-500/20'
                         }`}
                       >
-                        {h.format}
+                        {formatLabels[h.format] || 'Формат уточняется'}
                       </span>
                       {h.status === 'DEAD' && (
                         <span className="px-2.5 py-1 text-xs font-bold uppercase tracking-wider rounded-md border bg-red-500/10 text-red-400 border-red-500/20 backdrop-blur-sm">
@@ -239,7 +263,7 @@ export default function Home() {
                             <path fillRule="evenodd" d="M18 10a8 8 0 11-16 0 8 8 0 0116 0zm-5.5-2.5a2.5 2.5 0 11-5 0 2.5 2.5 0 015 0zM10 12a5.99 5.99 0 00-4.793 2.39A6.483 6.483 0 0010 16.5a6.483 6.483 0 004.793-2.11A5.99 5.99 0 0010 12z" clipRule="evenodd" />
                           </svg>
                         </div>
-                        <span className="truncate">Возраст: {h.ageLimit}</span>
+                        <span className="truncate">Возраст: {ageLabel(h)}</span>
                       </div>
                     </div>
                   </div>